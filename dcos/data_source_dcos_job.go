@@ -1,11 +1,11 @@
 package dcos
 
 import (
-	"context"
 	"log"
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func dataSourceDcosJob() *schema.Resource {
@@ -188,7 +188,8 @@ func dataSourceDcosJob() *schema.Resource {
 
 func dataSourceDcosJobRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	jobId := d.Get("name").(string)
 
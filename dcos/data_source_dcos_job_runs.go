@@ -0,0 +1,161 @@
+package dcos
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/dcos/client-go/dcos"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
+)
+
+func dataSourceDcosJobRuns() *schema.Resource {
+	jobRunSchema := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"run_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"finished_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"task_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read: dataSourceDcosJobRunsRead,
+		Schema: map[string]*schema.Schema{
+			"job_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Unique identifier for the job.",
+			},
+			"active_runs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        jobRunSchema,
+				Description: "The runs of the job in progress",
+			},
+			"finished_runs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        jobRunSchema,
+				Description: "The finished runs Metronome keeps in the job history, latest first",
+			},
+			"success_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"failure_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"last_success_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_failure_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the latest finished run, empty if the job never finished",
+			},
+		},
+	}
+}
+
+func dataSourceDcosJobRunsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*dcos.APIClient)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	jobID := d.Get("job_id").(string)
+
+	// V1GetJobIdRuns and V1GetJob decode runs as job definitions, which drops
+	// their status and the history
+	activeRuns, _, err := getJobRuns(ctx, client, jobID)
+	if err != nil {
+		return util.StepError(ctx, "reading the runs of job "+jobID, err)
+	}
+
+	history, _, err := getJobHistory(ctx, client, jobID)
+	if err != nil {
+		return util.StepError(ctx, "reading the history of job "+jobID, err)
+	}
+
+	log.Printf("[TRACE] Metronome runs of %s: %+v, history: %+v", jobID, activeRuns, history)
+
+	finishedRuns := make([]metronomeJobRun, 0)
+	for _, run := range history.SuccessfulFinishedRuns {
+		finishedRuns = append(finishedRuns, run.jobRun(jobID, jobRunSuccess))
+	}
+	for _, run := range history.FailedFinishedRuns {
+		finishedRuns = append(finishedRuns, run.jobRun(jobID, jobRunFailed))
+	}
+	// Timestamps only have a resolution of seconds, run IDs start with the
+	// time the run was created
+	sort.SliceStable(finishedRuns, func(i, j int) bool {
+		if finishedRuns[i].CompletedAt != finishedRuns[j].CompletedAt {
+			return finishedRuns[i].CompletedAt > finishedRuns[j].CompletedAt
+		}
+		return finishedRuns[i].ID > finishedRuns[j].ID
+	})
+
+	sort.SliceStable(activeRuns, func(i, j int) bool {
+		return activeRuns[i].CreatedAt > activeRuns[j].CreatedAt
+	})
+
+	d.SetId(jobID)
+
+	if err := d.Set("active_runs", flattenJobRuns(activeRuns)); err != nil {
+		return fmt.Errorf("Unable to set active_runs: %s", err.Error())
+	}
+	if err := d.Set("finished_runs", flattenJobRuns(finishedRuns)); err != nil {
+		return fmt.Errorf("Unable to set finished_runs: %s", err.Error())
+	}
+
+	d.Set("success_count", history.SuccessCount)
+	d.Set("failure_count", history.FailureCount)
+	d.Set("last_success_at", history.LastSuccessAt)
+	d.Set("last_failure_at", history.LastFailureAt)
+
+	lastStatus := ""
+	if len(finishedRuns) > 0 {
+		lastStatus = finishedRuns[0].Status
+	}
+	d.Set("last_status", lastStatus)
+
+	return nil
+}
+
+func flattenJobRuns(runs []metronomeJobRun) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	for _, run := range runs {
+		result = append(result, map[string]interface{}{
+			"run_id":      run.ID,
+			"status":      run.Status,
+			"created_at":  run.CreatedAt,
+			"finished_at": run.CompletedAt,
+			"task_ids":    run.taskIDs(),
+		})
+	}
+	return result
+}
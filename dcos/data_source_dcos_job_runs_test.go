@@ -0,0 +1,87 @@
+package dcos
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test reading the active runs and the history of a job */
+func TestDcosJobRunsDataSource_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	// The data source is only added once the run was applied, so it is read
+	// after the run finished
+	config := func(revision string, wait bool, withData bool) string {
+		config := testJobRunConfig(server, fmt.Sprintf(`
+resource "dcos_job_run" "test" {
+  job_id = dcos_job.test.name
+  wait   = %t
+
+  triggers = {
+    revision = %q
+  }
+}
+`, wait, revision))
+		if withData {
+			config += `
+data "dcos_job_runs" "test" {
+  job_id = dcos_job.test.name
+}
+`
+		}
+		return config
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: config("1", true, false),
+			},
+			{
+				Config: config("1", true, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "success_count", "1"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "failure_count", "0"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "last_status", "SUCCESS"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "active_runs.#", "0"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "finished_runs.#", "1"),
+					resource.TestCheckResourceAttrPair("data.dcos_job_runs.test", "finished_runs.0.run_id", "dcos_job_run.test", "id"),
+					resource.TestCheckResourceAttrSet("data.dcos_job_runs.test", "last_success_at"),
+				),
+			},
+			{
+				PreConfig: func() {
+					server.FailJobRuns("test.job", true)
+				},
+				Config:      config("2", true, false),
+				ExpectError: regexp.MustCompile(`Run \S+ of job test.job failed`),
+			},
+			{
+				PreConfig: func() {
+					server.FailJobRuns("test.job", false)
+					server.HoldJobRuns("test.job", true)
+				},
+				Config: config("2", false, false),
+			},
+			{
+				Config: config("2", false, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "success_count", "1"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "failure_count", "1"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "last_status", "FAILED"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "finished_runs.#", "2"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "finished_runs.0.status", "FAILED"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "active_runs.#", "1"),
+					resource.TestCheckResourceAttr("data.dcos_job_runs.test", "active_runs.0.status", "ACTIVE"),
+					resource.TestCheckResourceAttrPair("data.dcos_job_runs.test", "active_runs.0.run_id", "dcos_job_run.test", "id"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,193 @@
+package dcos
+
+import (
+	"fmt"
+	"log"
+
+	marathon "github.com/gambol99/go-marathon"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
+)
+
+func dataSourceDcosMarathonAppTasks() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDcosMarathonAppTasksRead,
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the Marathon app",
+			},
+			"marathon_service_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "service/marathon",
+				Description: "The service URL of the Marathon instance running the app, to support Marathon-on-Marathon",
+			},
+			"healthy_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only return the healthy tasks. Running tasks count as healthy if the app has no health checks.",
+			},
+			"port_index": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The index of the host port the endpoints point to",
+			},
+			"endpoints": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The host:port pairs of the tasks having a host port at port_index",
+			},
+			"tasks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"agent_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ports": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"service_ports": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"ip_addresses": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"healthy": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"staged_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"started_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDcosMarathonAppTasksRead(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
+	if err != nil {
+		return err
+	}
+
+	appID := d.Get("app_id").(string)
+	healthyOnly := d.Get("healthy_only").(bool)
+	portIndex := d.Get("port_index").(int)
+
+	app, err := config.Client.Application(appID)
+	if err != nil {
+		return util.StepError(ctx, "reading app "+appID, err)
+	}
+
+	tasks, err := config.Client.Tasks(appID)
+	if err != nil {
+		return util.StepError(ctx, "reading the tasks of app "+appID, err)
+	}
+
+	log.Printf("[TRACE] Marathon tasks of %s: %+v", appID, tasks.Tasks)
+
+	taskList := make([]map[string]interface{}, 0)
+	endpoints := make([]string, 0)
+	for _, task := range tasks.Tasks {
+		healthy := isTaskHealthy(app, task)
+		if healthyOnly && !healthy {
+			continue
+		}
+
+		ipAddresses := make([]string, 0)
+		for _, address := range task.IPAddresses {
+			ipAddresses = append(ipAddresses, address.IPAddress)
+		}
+
+		taskList = append(taskList, map[string]interface{}{
+			"id":            task.ID,
+			"host":          task.Host,
+			"agent_id":      task.SlaveID,
+			"state":         task.State,
+			"ports":         task.Ports,
+			"service_ports": task.ServicePorts,
+			"ip_addresses":  ipAddresses,
+			"healthy":       healthy,
+			"staged_at":     task.StagedAt,
+			"started_at":    task.StartedAt,
+			"version":       task.Version,
+		})
+
+		if portIndex < len(task.Ports) {
+			endpoints = append(endpoints, fmt.Sprintf("%s:%d", task.Host, task.Ports[portIndex]))
+		}
+	}
+
+	d.SetId(app.ID)
+
+	if err := d.Set("tasks", taskList); err != nil {
+		return fmt.Errorf("Unable to set tasks: %s", err.Error())
+	}
+	d.Set("endpoints", endpoints)
+
+	return nil
+}
+
+/**
+ * isTaskHealthy returns whether all health checks of the task pass. Running
+ * tasks of apps without health checks are considered healthy.
+ */
+func isTaskHealthy(app *marathon.Application, task marathon.Task) bool {
+	if task.State != "TASK_RUNNING" {
+		return false
+	}
+	if !app.HasHealthChecks() {
+		return true
+	}
+	if !task.HasHealthCheckResults() {
+		return false
+	}
+	for _, result := range task.HealthCheckResults {
+		if !result.Alive {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package dcos
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test reading the tasks and endpoints of an app, optionally healthy ones only */
+func TestDcosMarathonAppTasks_read(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	app := server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id    = "/test/app"
+  cmd       = "python3 -m http.server $PORT0"
+  instances = 3
+
+  port_definitions {
+    name = "http"
+  }
+
+  health_checks {
+    protocol   = "MESOS_HTTP"
+    path       = "/"
+    port_index = 0
+  }
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: app,
+			},
+			{
+				PreConfig: func() {
+					server.FailHealthChecks("", "/test/app", 1)
+				},
+				Config: app + `
+data "dcos_marathon_app_tasks" "all" {
+  app_id = "${dcos_marathon_app.test.app_id}"
+}
+
+data "dcos_marathon_app_tasks" "healthy" {
+  app_id       = "${dcos_marathon_app.test.app_id}"
+  healthy_only = true
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.all", "tasks.#", "3"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.all", "tasks.0.id", "test_app.instance-0"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.all", "tasks.0.host", "10.0.0.1"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.all", "tasks.0.state", "TASK_RUNNING"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.all", "tasks.0.ports.0", "31000"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.all", "tasks.0.ip_addresses.0", "10.0.0.1"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.all", "tasks.2.healthy", "false"),
+					resource.TestCheckResourceAttrSet("data.dcos_marathon_app_tasks.all", "tasks.0.started_at"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.healthy", "tasks.#", "2"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.healthy", "endpoints.#", "2"),
+					resource.TestCheckResourceAttr("data.dcos_marathon_app_tasks.healthy", "endpoints.1", "10.0.0.2:31010"),
+				),
+			},
+		},
+	})
+}
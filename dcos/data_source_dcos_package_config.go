@@ -463,7 +463,7 @@ func dataSourceDcosPackageConfigRead(d *schema.ResourceData, meta interface{}) e
 	if err != nil {
 		return fmt.Errorf("Could not merge config with upstream: %s", err.Error())
 	}
-	log.Printf("[TRACE] User config merged to: %s", util.PrintJSON(&configSpec.Config))
+	log.Printf("[TRACE] User config merged to: %s", util.PrintJSON(util.RedactJSON(configSpec.Config)))
 
 	// Compute a unique checksum from the checksum string fields
 	configSpec.Checksum = computeCsum(
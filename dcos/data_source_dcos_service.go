@@ -1,7 +1,10 @@
 package dcos
 
 import (
+	"log"
+
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func dataSourceDcosService() *schema.Resource {
@@ -9,9 +12,16 @@ func dataSourceDcosService() *schema.Resource {
 		Read: dataSourceDcosServiceRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the Marathon app",
+			},
+			"marathon_service_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "service/marathon",
+				Description: "The service URL of the Marathon instance running the app, to support Marathon-on-Marathon",
 			},
 			"instances": {
 				Type:     schema.TypeInt,
@@ -33,26 +43,102 @@ func dataSourceDcosService() *schema.Resource {
 				Type:     schema.TypeFloat,
 				Computed: true,
 			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"env": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The environment variables of the app, except for secrets",
+			},
+			"container_image": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Docker image of the app container, if any",
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tasks_running": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"tasks_healthy": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"deployments": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the deployments of the app in progress",
+			},
+			"deploying": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the app is being deployed",
+			},
 		},
 	}
 }
 
 func dataSourceDcosServiceRead(d *schema.ResourceData, meta interface{}) error {
-	// client := meta.(*dcos.Client)
-	//
-	// app, err := client.Marathon.MarathonClient.Application(d.Get("name").(string))
-	//
-	// if err != nil {
-	// 	return err
-	// }
-	//
-	// d.SetId(app.ID)
-	//
-	// d.Set("cmd", app.Cmd)
-	// d.Set("instances", app.Instances)
-	// d.Set("cpus", app.CPUs)
-	// d.Set("disk", app.Disk)
-	// d.Set("mem", app.Mem)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+
+	app, err := config.Client.Application(name)
+	if err != nil {
+		return util.StepError(ctx, "reading app "+name, err)
+	}
+
+	log.Printf("[TRACE] Marathon app: %+v", app)
+
+	d.SetId(app.ID)
+
+	d.Set("cmd", app.Cmd)
+	d.Set("instances", app.Instances)
+	d.Set("cpus", app.CPUs)
+	d.Set("disk", app.Disk)
+	d.Set("mem", app.Mem)
+	labels := make(map[string]string)
+	if app.Labels != nil {
+		labels = *app.Labels
+	}
+	d.Set("labels", labels)
+
+	env := make(map[string]string)
+	if app.Env != nil {
+		env = *app.Env
+	}
+	d.Set("env", env)
+
+	d.Set("version", app.Version)
+	d.Set("tasks_running", app.TasksRunning)
+	d.Set("tasks_healthy", app.TasksHealthy)
+
+	image := ""
+	if app.Container != nil && app.Container.Docker != nil {
+		image = app.Container.Docker.Image
+	}
+	d.Set("container_image", image)
+
+	deployments := make([]string, 0)
+	for _, deployment := range app.DeploymentIDs() {
+		deployments = append(deployments, deployment.DeploymentID)
+	}
+	d.Set("deployments", deployments)
+	d.Set("deploying", len(deployments) > 0)
 
 	return nil
 }
@@ -2,7 +2,6 @@ package dcos
 
 import (
 	"crypto"
-	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
@@ -11,15 +10,9 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
-type ServiceAccontJson struct {
-	Scheme        string `json:"scheme"`
-	UID           string `json:"uid"`
-	LoginEndpoint string `json:"login_endpoint"`
-	PrivateKey    string `json:"private_key"`
-}
-
 func dataSourceDcosServiceAccountSecret() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceDcosServiceAccountSecretRead,
@@ -70,41 +63,17 @@ func getKeyId(pubKey crypto.PublicKey) (string, error) {
 
 func dataSourceDcosServiceAccountSecretRead(d *schema.ResourceData, meta interface{}) error {
 	var (
-		pKey   *rsa.PrivateKey = nil
 		pemOut strings.Builder
-		sa     ServiceAccontJson
-		ok     bool
-		err    error
+		sa     util.ServiceAccountSecret
 	)
 
 	privateKeyContents := d.Get("private_key").(string)
 	loginEndpoint := d.Get("login_endpoint").(string)
 	uid := d.Get("uid").(string)
 
-	// Try parsing block as PEM
-	block, _ := pem.Decode([]byte(privateKeyContents))
-	if block == nil {
-		return fmt.Errorf("Unable to decode private key PEM data")
-	}
-
-	// First try parsing it as PKCS8-encapsulated private key
-	parseResult, err := x509.ParsePKCS8PrivateKey(block.Bytes)
-	if err != nil {
-		// If this fails, fall-back into parsing it as a plain PKCS1 private key
-		pKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
-		if err != nil {
-			return fmt.Errorf("Unable to parse PKCS8 or PKCS1 private key")
-		}
-	} else {
-		pKey, ok = parseResult.(*rsa.PrivateKey)
-		if !ok {
-			return fmt.Errorf("Unable to parse RSA private key")
-		}
-	}
-
-	err = pKey.Validate()
+	pKey, err := util.ParseRSAPrivateKey(privateKeyContents)
 	if err != nil {
-		return fmt.Errorf("Private key is not valid")
+		return err
 	}
 
 	// Re-encode as PKCS8 private key
@@ -114,7 +83,7 @@ func dataSourceDcosServiceAccountSecretRead(d *schema.ResourceData, meta interfa
 	}
 
 	// Re-encode as PEM private key
-	block = &pem.Block{
+	block := &pem.Block{
 		Type:    "PRIVATE KEY",
 		Headers: map[string]string{},
 		Bytes:   keyBytes,
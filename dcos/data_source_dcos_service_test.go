@@ -0,0 +1,67 @@
+package dcos
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test reading a Marathon app through the dcos_service data source */
+func TestDcosService_read(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id    = "/test/app"
+  cmd       = "sleep 3600"
+  instances = 2
+  cpus      = 0.5
+  mem       = 64
+
+  env = {
+    FOO = "bar"
+  }
+
+  labels = {
+    team = "core"
+  }
+
+  container {
+    type = "DOCKER"
+
+    docker {
+      image = "alpine:3.10"
+    }
+  }
+
+  networks {
+    mode = "HOST"
+  }
+}
+
+data "dcos_service" "test" {
+  name = "${dcos_marathon_app.test.app_id}"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dcos_service.test", "id", "/test/app"),
+					resource.TestCheckResourceAttr("data.dcos_service.test", "cmd", "sleep 3600"),
+					resource.TestCheckResourceAttr("data.dcos_service.test", "instances", "2"),
+					resource.TestCheckResourceAttr("data.dcos_service.test", "mem", "64"),
+					resource.TestCheckResourceAttr("data.dcos_service.test", "env.FOO", "bar"),
+					resource.TestCheckResourceAttr("data.dcos_service.test", "labels.team", "core"),
+					resource.TestCheckResourceAttr("data.dcos_service.test", "container_image", "alpine:3.10"),
+					resource.TestCheckResourceAttrPair("data.dcos_service.test", "version", "dcos_marathon_app.test", "version"),
+					resource.TestCheckResourceAttr("data.dcos_service.test", "tasks_running", "2"),
+					resource.TestCheckResourceAttr("data.dcos_service.test", "deploying", "false"),
+				),
+			},
+		},
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func dataSourceDcosToken() *schema.Resource {
@@ -23,8 +24,7 @@ func dataSourceDcosTokenRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
 	//ctx := context.TODO()
 
-	dcosConfig := client.CurrentDCOSConfig()
-	token := dcosConfig.ACSToken()
+	token := util.ACSToken(client)
 	d.Set("token", token)
 	d.SetId(token)
 
@@ -32,9 +32,12 @@ func dataSourceDcosVersion() *schema.Resource {
 
 func dataSourceDcosVersionRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ver, err := util.DCOSGetVersion(client)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	ver, err := util.DCOSGetVersion(ctx, client)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading the cluster version", err)
 	}
 
 	d.Set("version", ver.Version)
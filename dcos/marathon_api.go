@@ -0,0 +1,291 @@
+package dcos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	marathon "github.com/gambol99/go-marathon"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
+)
+
+/**
+ * marathonAppExtensions holds the app fields go-marathon does not model. They
+ * are merged into the app definitions sent to and read from Marathon.
+ */
+type marathonAppExtensions struct {
+	TTY            *bool                   `json:"tty,omitempty"`
+	ResourceLimits *map[string]interface{} `json:"resourceLimits,omitempty"`
+}
+
+/**
+ * apiRequest places a request against the Marathon API, using the HTTP client
+ * of the configuration. Errors are reported like go-marathon does.
+ */
+func (c *marathonConf) apiRequest(method, path string, body interface{}, result interface{}) error {
+	_, err := c.apiRequestWithHeaders(method, path, body, result)
+	return err
+}
+
+/**
+ * apiRequestWithHeaders works like apiRequest, but also returns the headers
+ * of the response
+ */
+func (c *marathonConf) apiRequestWithHeaders(method, path string, body interface{}, result interface{}) (http.Header, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to encode request: %s", err.Error())
+		}
+	}
+
+	endpoint := strings.TrimRight(c.config.URL, "/") + path
+	log.Printf("[TRACE] Marathon %s %s %s", method, endpoint, util.RedactBody(path, payload))
+
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, marathon.NewAPIError(resp.StatusCode, content)
+	}
+
+	if result == nil {
+		return resp.Header, nil
+	}
+	return resp.Header, json.Unmarshal(content, result)
+}
+
+/**
+ * appDefinition merges the extensions into the app definition
+ */
+func appDefinition(application *marathon.Application, extensions marathonAppExtensions) (map[string]interface{}, error) {
+	definition := make(map[string]interface{})
+	for _, part := range []interface{}{application, extensions} {
+		encoded, err := json.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(encoded, &definition); err != nil {
+			return nil, err
+		}
+	}
+	return definition, nil
+}
+
+/**
+ * createApplication works like the go-marathon CreateApplication, but sends
+ * the given definition as-is
+ */
+func (c *marathonConf) createApplication(definition map[string]interface{}) (*marathon.Application, error) {
+	result := new(marathon.Application)
+	if err := c.apiRequest(http.MethodPost, "/v2/apps", definition, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/**
+ * updateApplication works like the go-marathon UpdateApplication, but sends
+ * the given definition as-is. Unless partial, the definition replaces the
+ * current one, instead of being merged into it.
+ */
+func (c *marathonConf) updateApplication(id string, definition map[string]interface{}, force bool, partial bool) (*marathon.DeploymentID, error) {
+	query := url.Values{}
+	if force {
+		query.Set("force", "true")
+	}
+	if !partial {
+		query.Set("partialUpdate", "false")
+	}
+
+	path := "/v2/apps/" + strings.Trim(id, "/")
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	result := new(marathon.DeploymentID)
+	if err := c.apiRequest(http.MethodPut, path, definition, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/**
+ * applicationJSON returns the app definition as reported by Marathon
+ */
+func (c *marathonConf) applicationJSON(id string) (json.RawMessage, error) {
+	var wrapper struct {
+		Application json.RawMessage `json:"app"`
+	}
+
+	if err := c.apiRequest(http.MethodGet, "/v2/apps/"+strings.Trim(id, "/"), nil, &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.Application, nil
+}
+
+/**
+ * application works like the go-marathon Application, but also returns the
+ * extensions
+ */
+func (c *marathonConf) application(id string) (*marathon.Application, marathonAppExtensions, error) {
+	var extensions marathonAppExtensions
+
+	definition, err := c.applicationJSON(id)
+	if err != nil {
+		return nil, extensions, err
+	}
+
+	application := new(marathon.Application)
+	if err := json.Unmarshal(definition, application); err != nil {
+		return nil, extensions, err
+	}
+	if err := json.Unmarshal(definition, &extensions); err != nil {
+		return nil, extensions, err
+	}
+	return application, extensions, nil
+}
+
+/**
+ * podDefinition converts the pod into the definition sent to Marathon
+ */
+func podDefinition(pod *marathon.Pod) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	definition := make(map[string]interface{})
+	if err := json.Unmarshal(encoded, &definition); err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+/**
+ * createPod works like the go-marathon CreatePod, but sends the given
+ * definition as-is and returns the ID of the resulting deployment
+ */
+func (c *marathonConf) createPod(definition map[string]interface{}) (string, error) {
+	headers, err := c.apiRequestWithHeaders(http.MethodPost, "/v2/pods", definition, nil)
+	if err != nil {
+		return "", err
+	}
+	return headers.Get("Marathon-Deployment-Id"), nil
+}
+
+/**
+ * updatePod works like the go-marathon UpdatePod, but sends the given
+ * definition as-is and returns the ID of the resulting deployment
+ */
+func (c *marathonConf) updatePod(id string, definition map[string]interface{}, force bool) (string, error) {
+	path := "/v2/pods/" + strings.Trim(id, "/")
+	if force {
+		path += "?force=true"
+	}
+
+	headers, err := c.apiRequestWithHeaders(http.MethodPut, path, definition, nil)
+	if err != nil {
+		return "", err
+	}
+	return headers.Get("Marathon-Deployment-Id"), nil
+}
+
+/**
+ * podJSON returns the pod definition as reported by Marathon
+ */
+func (c *marathonConf) podJSON(id string) (json.RawMessage, error) {
+	var definition json.RawMessage
+	if err := c.apiRequest(http.MethodGet, "/v2/pods/"+strings.Trim(id, "/"), nil, &definition); err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+/**
+ * marathonGroup is a Marathon group, including the fields go-marathon does
+ * not model
+ */
+type marathonGroup struct {
+	ID           string            `json:"id"`
+	EnforceRole  *bool             `json:"enforceRole,omitempty"`
+	Dependencies []string          `json:"dependencies"`
+	Version      string            `json:"version,omitempty"`
+	Apps         []json.RawMessage `json:"apps,omitempty"`
+	Pods         []json.RawMessage `json:"pods,omitempty"`
+	Groups       []json.RawMessage `json:"groups,omitempty"`
+}
+
+/**
+ * createGroup works like the go-marathon CreateGroup, but also returns the
+ * resulting deployment
+ */
+func (c *marathonConf) createGroup(group *marathonGroup) (*marathon.DeploymentID, error) {
+	result := new(marathon.DeploymentID)
+	if err := c.apiRequest(http.MethodPost, "/v2/groups", group, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/**
+ * updateGroup works like the go-marathon UpdateGroup, but supports the
+ * fields go-marathon does not model
+ */
+func (c *marathonConf) updateGroup(id string, group *marathonGroup, force bool) (*marathon.DeploymentID, error) {
+	path := "/v2/groups/" + strings.Trim(id, "/")
+	if force {
+		path += "?force=true"
+	}
+
+	result := new(marathon.DeploymentID)
+	if err := c.apiRequest(http.MethodPut, path, group, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/**
+ * group works like the go-marathon Group, but supports the fields go-marathon
+ * does not model
+ */
+func (c *marathonConf) group(id string) (*marathonGroup, error) {
+	group := new(marathonGroup)
+	if err := c.apiRequest(http.MethodGet, "/v2/groups/"+strings.Trim(id, "/"), nil, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+/**
+ * deletePodInstances works like the go-marathon DeletePodInstances, but does
+ * not decode the killed instances, whose format differs between Marathon
+ * versions
+ */
+func (c *marathonConf) deletePodInstances(id string, instances []string) error {
+	return c.apiRequest(http.MethodDelete, "/v2/pods/"+strings.Trim(id, "/")+"::instances", instances, nil)
+}
@@ -0,0 +1,356 @@
+package dcos
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
+)
+
+/**
+ * Fields Marathon reports about the state of an app or pod, which are not
+ * part of its definition
+ */
+var marathonStatusFields = []string{
+	"version",
+	"versionInfo",
+	"tasks",
+	"tasksStaged",
+	"tasksRunning",
+	"tasksHealthy",
+	"tasksUnhealthy",
+	"taskStats",
+	"deployments",
+	"lastTaskFailure",
+	"readinessCheckResults",
+}
+
+/**
+ * Fields holding ports Marathon assigns when requested as 0
+ */
+var assignedPortFields = map[string]bool{
+	"port":        true,
+	"hostPort":    true,
+	"servicePort": true,
+}
+
+/**
+ * The values Marathon fills in for the fields missing from app definitions.
+ * Lists hold the defaults of each of their elements.
+ */
+var marathonAppDefinitionDefaults = map[string]interface{}{
+	"instances":             1.0,
+	"cpus":                  1.0,
+	"mem":                   128.0,
+	"disk":                  0.0,
+	"gpus":                  0.0,
+	"executor":              "",
+	"backoffSeconds":        1.0,
+	"backoffFactor":         1.15,
+	"maxLaunchDelaySeconds": 3600.0,
+	"requirePorts":          false,
+	"killSelection":         "YOUNGEST_FIRST",
+	"role":                  "*",
+	"user":                  "",
+	"upgradeStrategy": map[string]interface{}{
+		"minimumHealthCapacity": 1.0,
+		"maximumOverCapacity":   1.0,
+	},
+	"unreachableStrategy": map[string]interface{}{
+		"inactiveAfterSeconds": 0.0,
+		"expungeAfterSeconds":  0.0,
+	},
+	"networks": []interface{}{
+		map[string]interface{}{
+			"mode": "host",
+		},
+	},
+	"portDefinitions": []interface{}{
+		map[string]interface{}{
+			"protocol": "tcp",
+			"name":     "default",
+		},
+	},
+	"container": map[string]interface{}{
+		"docker": map[string]interface{}{
+			"forcePullImage": false,
+			"privileged":     false,
+		},
+		"portMappings": []interface{}{
+			map[string]interface{}{
+				"protocol":    "tcp",
+				"servicePort": 0.0,
+			},
+		},
+	},
+	"healthChecks": []interface{}{
+		map[string]interface{}{
+			"gracePeriodSeconds":     300.0,
+			"intervalSeconds":        60.0,
+			"timeoutSeconds":         20.0,
+			"maxConsecutiveFailures": 3.0,
+			"delaySeconds":           15.0,
+			"ignoreHttp1xx":          false,
+			"portIndex":              0.0,
+		},
+	},
+	"readinessChecks": []interface{}{
+		map[string]interface{}{
+			"httpStatusCodesForReady": []interface{}{200.0},
+			"preserveLastResponse":    false,
+		},
+	},
+}
+
+/**
+ * The values Marathon fills in for the fields missing from pod definitions
+ */
+var marathonPodDefinitionDefaults = map[string]interface{}{
+	"role": "*",
+	"user": "",
+	"scaling": map[string]interface{}{
+		"kind":      "fixed",
+		"instances": 1.0,
+	},
+	"executorResources": map[string]interface{}{
+		"cpus": 0.1,
+		"mem":  32.0,
+		"disk": 10.0,
+	},
+	"networks": []interface{}{
+		map[string]interface{}{
+			"mode": "host",
+		},
+	},
+	"scheduling": map[string]interface{}{
+		"backoff": map[string]interface{}{
+			"backoff":        1.0,
+			"backoffFactor":  1.15,
+			"maxLaunchDelay": 3600.0,
+		},
+		"upgrade": map[string]interface{}{
+			"minimumHealthCapacity": 1.0,
+			"maximumOverCapacity":   1.0,
+		},
+		"killSelection": "YOUNGEST_FIRST",
+		"unreachableStrategy": map[string]interface{}{
+			"inactiveAfterSeconds": 0.0,
+			"expungeAfterSeconds":  0.0,
+		},
+	},
+	"containers": []interface{}{
+		map[string]interface{}{
+			"resources": map[string]interface{}{
+				"disk": 0.0,
+				"gpus": 0.0,
+			},
+		},
+	},
+}
+
+/**
+ * parseDefinitionJSON parses the definition of an app or pod and sets its ID
+ */
+func parseDefinitionJSON(definitionJSON string, id string) (map[string]interface{}, error) {
+	var definition map[string]interface{}
+	if err := json.Unmarshal([]byte(definitionJSON), &definition); err != nil {
+		return nil, fmt.Errorf("Unable to parse definition_json: %s", err.Error())
+	}
+
+	if definedID, ok := definition["id"].(string); ok && marathonPath(definedID) != marathonPath(id) {
+		return nil, fmt.Errorf("The id %q in definition_json does not match %q", definedID, id)
+	}
+	definition["id"] = id
+
+	return definition, nil
+}
+
+/**
+ * parseMarathonImportID splits an import ID of the form
+ * `[<marathon_service_url>:]<id>` into the service URL, which defaults to the
+ * root Marathon, and the absolute ID. The name of the ID is used in errors.
+ */
+func parseMarathonImportID(importID string, idName string) (string, string, error) {
+	serviceURL := "service/marathon"
+	id := importID
+
+	if idx := strings.LastIndex(id, ":"); idx >= 0 {
+		serviceURL = strings.Trim(id[:idx], "/")
+		id = id[idx+1:]
+	}
+
+	if serviceURL == "" || strings.Trim(id, "/") == "" {
+		return "", "", fmt.Errorf("Invalid import ID %q, expecting [<marathon_service_url>:]<%s>", importID, idName)
+	}
+
+	return serviceURL, marathonPath(id), nil
+}
+
+func marathonPath(id string) string {
+	if len(id) > 0 && id[0] == '/' {
+		return id
+	}
+	return "/" + id
+}
+
+/**
+ * normalizeDefinitionJSON converts the definition Marathon reports into the
+ * form of the configured definition. Status fields, and fields the
+ * configuration omits while Marathon reports their defaults, are removed.
+ */
+func normalizeDefinitionJSON(reported map[string]interface{}, configuredJSON string, defaults map[string]interface{}) (string, error) {
+	var configured map[string]interface{}
+	if err := json.Unmarshal([]byte(configuredJSON), &configured); err != nil {
+		configured = make(map[string]interface{})
+	}
+
+	definition := make(map[string]interface{})
+	for k, v := range reported {
+		definition[k] = v
+	}
+	for _, field := range append(marathonStatusFields, "id") {
+		if _, ok := configured[field]; !ok {
+			delete(definition, field)
+		}
+	}
+
+	stripped := stripDefinitionDefaults(definition, configured, defaults)
+	encoded, err := json.Marshal(stripped)
+	if err != nil {
+		return "", err
+	}
+
+	return util.NormalizeJSON(string(encoded))
+}
+
+/**
+ * stripDefinitionDefaults removes the values the configuration omits, if
+ * they are empty or the defaults of Marathon
+ */
+func stripDefinitionDefaults(reported interface{}, configured interface{}, defaults interface{}) interface{} {
+	switch value := reported.(type) {
+	case map[string]interface{}:
+		configuredMap, _ := configured.(map[string]interface{})
+		defaultsMap, _ := defaults.(map[string]interface{})
+
+		ret := make(map[string]interface{})
+		for k, v := range value {
+			if c, ok := configuredMap[k]; ok {
+				// Marathon assigns the ports requested as 0
+				if assignedPortFields[k] && c == 0.0 {
+					ret[k] = c
+					continue
+				}
+				ret[k] = stripDefinitionDefaults(v, c, defaultsMap[k])
+				continue
+			}
+
+			if assignedPortFields[k] {
+				continue
+			}
+
+			v = stripDefinitionDefaults(v, nil, defaultsMap[k])
+			if isEmptyDefinitionValue(v) || reflect.DeepEqual(v, defaultsMap[k]) {
+				continue
+			}
+			ret[k] = v
+		}
+		return ret
+
+	case []interface{}:
+		configuredList, _ := configured.([]interface{})
+
+		// The defaults of lists apply to each of their elements
+		var elementDefaults interface{}
+		if defaultsList, ok := defaults.([]interface{}); ok && len(defaultsList) == 1 {
+			elementDefaults = defaultsList[0]
+		}
+
+		ret := make([]interface{}, len(value))
+		for i, v := range value {
+			var c interface{}
+			if i < len(configuredList) {
+				c = configuredList[i]
+			}
+			ret[i] = stripDefinitionDefaults(v, c, elementDefaults)
+		}
+		return ret
+	}
+
+	return reported
+}
+
+func isEmptyDefinitionValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		for _, element := range v {
+			if !isEmptyDefinitionValue(element) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+/**
+ * validateDefinitionJSON checks that the definition is a JSON object
+ */
+func validateDefinitionJSON(v interface{}, k string) (ws []string, errors []error) {
+	var definition map[string]interface{}
+	if err := json.Unmarshal([]byte(v.(string)), &definition); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a JSON object: %s", k, err.Error()))
+	}
+	return
+}
+
+/**
+ * suppressEquivalentDefinitionJSON ignores differences in formatting and key
+ * order of JSON definitions
+ */
+func suppressEquivalentDefinitionJSON(k, old, new string, d *schema.ResourceData) bool {
+	oldJSON, err := util.NormalizeJSON(old)
+	if err != nil {
+		return false
+	}
+	newJSON, err := util.NormalizeJSON(new)
+	if err != nil {
+		return false
+	}
+	return oldJSON == newJSON
+}
+
+/**
+ * definitionJSONSchema returns the schema of the definition_json attribute,
+ * which conflicts with all structured fields of the resource
+ */
+func definitionJSONSchema(resourceSchema map[string]*schema.Schema, keep ...string) *schema.Schema {
+	kept := map[string]bool{}
+	for _, k := range keep {
+		kept[k] = true
+	}
+
+	var conflicts []string
+	for k, s := range resourceSchema {
+		if kept[k] || (s.Computed && !s.Optional) {
+			continue
+		}
+		conflicts = append(conflicts, k)
+	}
+
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ConflictsWith:    conflicts,
+		ValidateFunc:     validateDefinitionJSON,
+		DiffSuppressFunc: suppressEquivalentDefinitionJSON,
+		Description:      "The Marathon JSON definition, used instead of the structured fields",
+	}
+}
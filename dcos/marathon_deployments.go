@@ -0,0 +1,374 @@
+package dcos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dcos/client-go/dcos"
+	marathon "github.com/gambol99/go-marathon"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
+)
+
+/**
+ * How often waiters check if their deployment is still running, in case the
+ * event stream is unavailable or missed the deployment events
+ */
+var deploymentPollInterval = 5 * time.Second
+
+/**
+ * How long operations wait for the event stream subscription before they
+ * deploy anyway, polling for the deployment instead
+ */
+var deploymentSubscribeTimeout = 5 * time.Second
+
+/**
+ * How long finished deployments are remembered, for waiters that start
+ * waiting after the deployment events were received
+ */
+const deploymentEventRetention = 10 * time.Minute
+
+type deploymentEvent struct {
+	id    string
+	state string
+	at    time.Time
+}
+
+/**
+ * result converts the deployment event into the outcome of the deployment
+ */
+func (e deploymentEvent) result() error {
+	if e.state == "deployment_failed" {
+		return errors.New("Received deployment_failed event from marathon")
+	}
+	return nil
+}
+
+/**
+ * deploymentWatcher keeps a single event stream subscription to a Marathon
+ * instance, and dispatches the deployment events to everyone waiting on them
+ */
+type deploymentWatcher struct {
+	url    string
+	client *dcos.APIClient
+
+	lock         sync.Mutex
+	subscription chan struct{}
+	finished     map[string]deploymentEvent
+	waiters      map[string][]chan deploymentEvent
+}
+
+var deploymentWatchers = struct {
+	sync.Mutex
+	watchers map[string]*deploymentWatcher
+}{
+	watchers: make(map[string]*deploymentWatcher),
+}
+
+/**
+ * getDeploymentWatcher returns the deployment watcher shared by all resources
+ * using the given provider and Marathon instance
+ */
+func getDeploymentWatcher(client *dcos.APIClient, url string) *deploymentWatcher {
+	deploymentWatchers.Lock()
+	defer deploymentWatchers.Unlock()
+
+	key := fmt.Sprintf("%p:%s", client, url)
+	if w, ok := deploymentWatchers.watchers[key]; ok {
+		return w
+	}
+
+	w := &deploymentWatcher{
+		url:      url,
+		client:   client,
+		finished: make(map[string]deploymentEvent),
+		waiters:  make(map[string][]chan deploymentEvent),
+	}
+	deploymentWatchers.watchers[key] = w
+	return w
+}
+
+/**
+ * subscribe starts listening to the Marathon event stream in the background,
+ * unless already started, and waits for the subscription as long as the
+ * operation allows. Waiters fall back to polling while the subscription is
+ * pending, or if it failed, which is not retried.
+ */
+func (w *deploymentWatcher) subscribe(ctx context.Context) {
+	w.lock.Lock()
+	if w.subscription == nil {
+		w.subscription = make(chan struct{})
+		go w.listen(w.subscription)
+	}
+	subscription := w.subscription
+	w.lock.Unlock()
+
+	timer := time.NewTimer(deploymentSubscribeTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-subscription:
+	case <-timer.C:
+		log.Printf("[WARN] Subscribing to events of %s takes too long, polling for deployments", w.url)
+	case <-ctx.Done():
+	}
+}
+
+/**
+ * listen subscribes to the Marathon event stream and dispatches its events,
+ * closing the given channel once the subscription succeeded or failed
+ */
+func (w *deploymentWatcher) listen(subscription chan struct{}) {
+	// The subscription outlives the operation that started it, so it must not
+	// use a client bound to a context
+	config := marathon.NewDefaultConfig()
+	config.URL = w.url
+	config.HTTPClient = w.client.HTTPClient()
+	config.HTTPSSEClient = w.client.HTTPClient()
+	config.EventsTransport = marathon.EventsTransportSSE
+
+	client, err := marathon.NewClient(config)
+	if err != nil {
+		close(subscription)
+		log.Printf("[WARN] Unable to create marathon client for %s, polling for deployments: %s", w.url, err.Error())
+		return
+	}
+
+	events, err := client.AddEventsListener(marathon.EventIDDeploymentSuccess | marathon.EventIDDeploymentFailed)
+	close(subscription)
+	if err != nil {
+		log.Printf("[WARN] Unable to subscribe to events of %s, polling for deployments: %s", w.url, err.Error())
+		return
+	}
+
+	w.dispatch(events)
+}
+
+/**
+ * dispatch forwards the deployment events to the waiters
+ */
+func (w *deploymentWatcher) dispatch(events marathon.EventsChannel) {
+	for event := range events {
+		var id string
+		switch mEvent := event.Event.(type) {
+		case *marathon.EventDeploymentSuccess:
+			id = mEvent.ID
+		case *marathon.EventDeploymentFailed:
+			id = mEvent.ID
+		default:
+			continue
+		}
+
+		log.Printf("[TRACE] Received %s event for deployment %s", event.Name, id)
+		dEvent := deploymentEvent{id: id, state: event.Name, at: time.Now()}
+
+		w.lock.Lock()
+		for fid, fEvent := range w.finished {
+			if time.Since(fEvent.at) > deploymentEventRetention {
+				delete(w.finished, fid)
+			}
+		}
+		w.finished[id] = dEvent
+		for _, c := range w.waiters[id] {
+			c <- dEvent
+		}
+		delete(w.waiters, id)
+		w.lock.Unlock()
+	}
+}
+
+/**
+ * finishedEvent returns the event of a finished deployment, if it was received
+ */
+func (w *deploymentWatcher) finishedEvent(id string) (deploymentEvent, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	dEvent, ok := w.finished[id]
+	return dEvent, ok
+}
+
+/**
+ * deploymentTarget is what a deployment is expected to deploy, to confirm its
+ * outcome if it finished without receiving its events. The version is left
+ * empty if it is not known in advance.
+ */
+type deploymentTarget struct {
+	version string
+	apps    []string
+	pods    []string
+	groups  []string
+}
+
+/**
+ * wait blocks until the given deployment is completed, either by receiving its
+ * events, or by polling the deployments with the given client
+ */
+func (w *deploymentWatcher) wait(ctx context.Context, config marathonConf, id string, target deploymentTarget) error {
+	c := make(chan deploymentEvent, 1)
+
+	w.lock.Lock()
+	dEvent, ok := w.finished[id]
+	if !ok {
+		w.waiters[id] = append(w.waiters[id], c)
+	}
+	w.lock.Unlock()
+
+	if ok {
+		return dEvent.result()
+	}
+	defer w.removeWaiter(id, c)
+
+	ticker := time.NewTicker(deploymentPollInterval)
+	defer ticker.Stop()
+
+	// The deployment is polled right away, to learn what it deploys in case
+	// it finishes without receiving its events
+	poll := time.After(0)
+
+	for {
+		select {
+		case dEvent := <-c:
+			return dEvent.result()
+
+		case <-poll:
+			poll = ticker.C
+
+			running, err := findDeployment(config.Client, id)
+			if err != nil {
+				if ctx.Err() != nil {
+					return util.StepError(ctx, "waiting for deployment "+id, err)
+				}
+				log.Printf("[WARN] Unable to query deployment %s: %s", id, err.Error())
+				continue
+			}
+			if running != nil {
+				target.version = running.Version
+				target.apps = running.AffectedApps
+				target.pods = running.AffectedPods
+				continue
+			}
+
+			// The events might have arrived while polling
+			if dEvent, ok := w.finishedEvent(id); ok {
+				return dEvent.result()
+			}
+
+			log.Printf("[DEBUG] Deployment %s is no longer running, without receiving its events", id)
+			return verifyDeployment(config, id, target)
+
+		case <-ctx.Done():
+			return util.StepError(ctx, "waiting for deployment "+id, ctx.Err())
+		}
+	}
+}
+
+/**
+ * findDeployment returns the given deployment, or nil if it is not running
+ */
+func findDeployment(client marathon.Marathon, id string) (*marathon.Deployment, error) {
+	deployments, err := client.Deployments()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, deployment := range deployments {
+		if deployment.ID == id {
+			return deployment, nil
+		}
+	}
+	return nil, nil
+}
+
+/**
+ * verifyDeployment checks the outcome of a deployment which finished without
+ * receiving its events. It succeeded if all affected apps, pods and groups
+ * are at the deployed version, or removed, and the apps and pods run all of
+ * their instances.
+ */
+func verifyDeployment(config marathonConf, id string, target deploymentTarget) error {
+	if len(target.apps) == 0 && len(target.pods) == 0 && len(target.groups) == 0 {
+		return fmt.Errorf("Outcome of deployment %s is unknown, it finished before it was seen running", id)
+	}
+
+	for _, appID := range target.apps {
+		app, err := config.Client.Application(appID)
+		if err != nil {
+			// Removed by the deployment
+			if apiErr, ok := err.(*marathon.APIError); ok && apiErr.ErrCode == marathon.ErrCodeNotFound {
+				continue
+			}
+			return fmt.Errorf("Outcome of deployment %s is unknown, unable to read app %s: %s", id, appID, err.Error())
+		}
+
+		instances := 1
+		if app.Instances != nil {
+			instances = *app.Instances
+		}
+		healthy := 0
+		if app.HealthChecks != nil && len(*app.HealthChecks) > 0 {
+			healthy = instances
+		}
+
+		if (target.version != "" && app.Version != target.version) || app.TasksStaged > 0 || app.TasksRunning < instances || app.TasksHealthy < healthy {
+			return fmt.Errorf("Outcome of deployment %s is unknown, app %s is at version %s with %d of %d instances running, expected version %s", id, appID, app.Version, app.TasksRunning, instances, target.version)
+		}
+	}
+
+	for _, podID := range target.pods {
+		status, err := config.Client.PodStatus(podID)
+		if err != nil {
+			if apiErr, ok := err.(*marathon.APIError); ok && apiErr.ErrCode == marathon.ErrCodeNotFound {
+				continue
+			}
+			return fmt.Errorf("Outcome of deployment %s is unknown, unable to read pod %s: %s", id, podID, err.Error())
+		}
+
+		version := ""
+		if status.Spec != nil {
+			version = status.Spec.Version
+		}
+
+		if (target.version != "" && version != target.version) || status.Status != marathon.PodStateStable {
+			return fmt.Errorf("Outcome of deployment %s is unknown, pod %s is %s at version %s, expected version %s", id, podID, status.Status, version, target.version)
+		}
+	}
+
+	for _, groupID := range target.groups {
+		group, err := config.group(groupID)
+		if err != nil {
+			if apiErr, ok := err.(*marathon.APIError); ok && apiErr.ErrCode == marathon.ErrCodeNotFound {
+				continue
+			}
+			return fmt.Errorf("Outcome of deployment %s is unknown, unable to read group %s: %s", id, groupID, err.Error())
+		}
+
+		if target.version != "" && group.Version != target.version {
+			return fmt.Errorf("Outcome of deployment %s is unknown, group %s is at version %s, expected version %s", id, groupID, group.Version, target.version)
+		}
+	}
+
+	return nil
+}
+
+func (w *deploymentWatcher) removeWaiter(id string, c chan deploymentEvent) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	waiters := w.waiters[id]
+	for i, waiter := range waiters {
+		if waiter == c {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+
+	if len(waiters) == 0 {
+		delete(w.waiters, id)
+	} else {
+		w.waiters[id] = waiters
+	}
+}
@@ -0,0 +1,204 @@
+package dcos
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	marathon "github.com/gambol99/go-marathon"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+/**
+ * How long collecting the diagnostics of a failed deployment may take. The
+ * context of the failed operation has usually expired by then.
+ */
+const deploymentDiagnosticsTimeout = 30 * time.Second
+
+/**
+ * Descriptions of the reasons Marathon gives for declining offers
+ */
+var offerDeclineReasons = map[string]string{
+	"UnfulfilledRole":                 "role not matched",
+	"UnfulfilledConstraint":           "constraints not matched",
+	"NoCorrespondingReservationFound": "no matching reservation",
+	"AgentMaintenance":                "agent in maintenance",
+	"InsufficientCpus":                "insufficient cpus",
+	"InsufficientMemory":              "insufficient mem",
+	"InsufficientDisk":                "insufficient disk",
+	"InsufficientGpus":                "insufficient gpus",
+	"InsufficientPorts":               "insufficient ports",
+	"DeclinedScarceResources":         "declined scarce resources",
+}
+
+/**
+ * collectDeploymentDiagnostics reads the launch queue status and the last task
+ * failure of the app, or the termination history of the pod, after its
+ * deployment failed
+ */
+func collectDeploymentDiagnostics(d *schema.ResourceData, meta interface{}, id string, isPod bool) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), deploymentDiagnosticsTimeout)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
+	if err != nil {
+		log.Printf("[WARN] Unable to collect diagnostics for %s: %s", id, err.Error())
+		return nil
+	}
+
+	return deploymentDiagnostics(config.Client, id, isPod)
+}
+
+/**
+ * withDeploymentDiagnostics adds the diagnostics to the error of a failed
+ * deployment
+ */
+func withDeploymentDiagnostics(err error, diagnostics []string) error {
+	if len(diagnostics) == 0 {
+		return err
+	}
+	return fmt.Errorf("%s\n\n%s", err.Error(), strings.Join(diagnostics, "\n"))
+}
+
+/**
+ * deploymentDiagnostics describes why the instances of an app or pod are not
+ * launched, as far as Marathon knows
+ */
+func deploymentDiagnostics(client marathon.Marathon, id string, isPod bool) []string {
+	var ret []string
+
+	queue, err := client.Queue()
+	if err != nil {
+		log.Printf("[WARN] Unable to read the launch queue: %s", err.Error())
+	} else {
+		for _, item := range queue.Items {
+			if queueItemID(item) == id {
+				ret = append(ret, describeQueueItem(item)...)
+			}
+		}
+	}
+
+	// Marathon only keeps the last task failure for apps, and the termination
+	// history for pods
+	if !isPod {
+		app, err := client.Application(id)
+		if err != nil {
+			log.Printf("[WARN] Unable to read app %s: %s", id, err.Error())
+		} else if app.LastTaskFailure != nil {
+			failure := app.LastTaskFailure
+			ret = append(ret, fmt.Sprintf("Last task failure: %s (%s at %s on %s)", failure.Message, failure.State, failure.Timestamp, failure.Host))
+		}
+	} else {
+		status, err := client.PodStatus(id)
+		if err != nil {
+			log.Printf("[WARN] Unable to read the status of pod %s: %s", id, err.Error())
+		} else {
+			ret = append(ret, describePodStatus(status)...)
+		}
+	}
+
+	return ret
+}
+
+/**
+ * describePodStatus summarizes the instances of the pod which are not stable,
+ * and why instances and their containers terminated
+ */
+func describePodStatus(status *marathon.PodStatus) []string {
+	var ret []string
+
+	if status.Message != "" {
+		ret = append(ret, fmt.Sprintf("Pod is %s: %s", status.Status, status.Message))
+	}
+
+	for _, instance := range status.Instances {
+		if instance.Status == marathon.PodInstanceStateStable {
+			continue
+		}
+		line := fmt.Sprintf("Instance %s is %s on %s", instance.ID, instance.Status, instance.AgentHostname)
+		if instance.Message != "" {
+			line += ": " + instance.Message
+		}
+		ret = append(ret, line)
+
+		for _, container := range instance.Containers {
+			if container.Termination != nil {
+				ret = append(ret, describeContainerTermination(container.Name, container.Status, container.Termination))
+			}
+		}
+	}
+
+	for _, termination := range status.TerminationHistory {
+		ret = append(ret, fmt.Sprintf("Instance %s terminated at %s: %s", termination.InstanceID, termination.TerminatedAt, termination.Message))
+
+		for _, container := range termination.Containers {
+			if container.Termination != nil {
+				ret = append(ret, describeContainerTermination(container.ContainerID, container.LastKnownState, container.Termination))
+			}
+		}
+	}
+
+	return ret
+}
+
+func describeContainerTermination(name string, state string, termination *marathon.ContainerTerminationState) string {
+	line := fmt.Sprintf("  Container %s (%s) exited with code %d", name, state, termination.ExitCode)
+	if termination.Message != "" {
+		line += ": " + termination.Message
+	}
+	return line
+}
+
+func queueItemID(item marathon.Item) string {
+	if item.Application != nil {
+		return item.Application.ID
+	}
+	if item.Pod != nil {
+		return item.Pod.ID
+	}
+	return ""
+}
+
+/**
+ * describeQueueItem summarizes why the offers for a launch queue item were
+ * declined
+ */
+func describeQueueItem(item marathon.Item) []string {
+	ret := []string{
+		fmt.Sprintf("Waiting to launch %d instance(s) with role %q since %s", item.Count, item.Role, item.Since),
+	}
+
+	if !item.Delay.Overdue && item.Delay.TimeLeftSeconds > 0 {
+		ret = append(ret, fmt.Sprintf("Launches are delayed for %ds after failures", item.Delay.TimeLeftSeconds))
+	}
+
+	summary := item.ProcessedOffersSummary
+	steps := summary.RejectSummaryLastOffers
+	if len(steps) == 0 {
+		steps = summary.RejectSummaryLaunchAttempt
+	}
+
+	var declines []string
+	for _, step := range steps {
+		if step.Declined == 0 {
+			continue
+		}
+		reason, ok := offerDeclineReasons[step.Reason]
+		if !ok {
+			reason = step.Reason
+		}
+		declines = append(declines, fmt.Sprintf("%s (%d of %d offers)", reason, step.Declined, step.Processed))
+	}
+	sort.Strings(declines)
+
+	if len(declines) > 0 {
+		ret = append(ret, fmt.Sprintf("Declined %d of %d offers: %s", summary.UnusedOffersCount, summary.ProcessedOffersCount, strings.Join(declines, ", ")))
+	} else if summary.ProcessedOffersCount == 0 {
+		ret = append(ret, "No offers were received")
+	}
+
+	return ret
+}
@@ -0,0 +1,308 @@
+package dcos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/dcos/client-go/dcos"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
+)
+
+// The final states of job runs reported by Metronome
+const (
+	jobRunSuccess = "SUCCESS"
+	jobRunFailed  = "FAILED"
+)
+
+/**
+ * metronomeJobRun is a run of a Metronome job. The generated client decodes
+ * runs as job definitions, which drops their status.
+ */
+type metronomeJobRun struct {
+	ID          string                `json:"id"`
+	JobID       string                `json:"jobId"`
+	Status      string                `json:"status"`
+	CreatedAt   string                `json:"createdAt"`
+	CompletedAt string                `json:"completedAt,omitempty"`
+	Tasks       []metronomeJobRunTask `json:"tasks"`
+}
+
+type metronomeJobRunTask struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	StartedAt   string `json:"startedAt,omitempty"`
+	CompletedAt string `json:"completedAt,omitempty"`
+}
+
+/**
+ * metronomeJobHistory holds the finished runs of a job Metronome still knows
+ */
+type metronomeJobHistory struct {
+	SuccessCount           int                    `json:"successCount"`
+	FailureCount           int                    `json:"failureCount"`
+	LastSuccessAt          string                 `json:"lastSuccessAt,omitempty"`
+	LastFailureAt          string                 `json:"lastFailureAt,omitempty"`
+	SuccessfulFinishedRuns []metronomeFinishedRun `json:"successfulFinishedRuns"`
+	FailedFinishedRuns     []metronomeFinishedRun `json:"failedFinishedRuns"`
+}
+
+type metronomeFinishedRun struct {
+	ID         string   `json:"id"`
+	CreatedAt  string   `json:"createdAt"`
+	FinishedAt string   `json:"finishedAt"`
+	Tasks      []string `json:"tasks"`
+}
+
+/**
+ * jobRun converts the finished run into the form of active runs
+ */
+func (r metronomeFinishedRun) jobRun(jobID string, status string) metronomeJobRun {
+	run := metronomeJobRun{
+		ID:          r.ID,
+		JobID:       jobID,
+		Status:      status,
+		CreatedAt:   r.CreatedAt,
+		CompletedAt: r.FinishedAt,
+		Tasks:       make([]metronomeJobRunTask, 0),
+	}
+	for _, task := range r.Tasks {
+		run.Tasks = append(run.Tasks, metronomeJobRunTask{ID: task})
+	}
+	return run
+}
+
+/**
+ * metronomeJobExtensions holds the fields of Metronome jobs the generated
+ * client lacks, or omits when they are zero
+ */
+type metronomeJobExtensions struct {
+	Dependencies []metronomeJobDependency  `json:"dependencies,omitempty"`
+	Run          metronomeJobRunExtensions `json:"run"`
+}
+
+type metronomeJobDependency struct {
+	ID string `json:"id"`
+}
+
+type metronomeJobRunExtensions struct {
+	Networks                   []metronomeJobNetwork      `json:"networks,omitempty"`
+	Ucr                        *metronomeJobUcrExtensions `json:"ucr,omitempty"`
+	Gpus                       *int                       `json:"gpus,omitempty"`
+	TaskKillGracePeriodSeconds *int                       `json:"taskKillGracePeriodSeconds,omitempty"`
+}
+
+type metronomeJobNetwork struct {
+	Mode   string            `json:"mode"`
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type metronomeJobUcrExtensions struct {
+	Image struct {
+		PullConfig *metronomeJobPullConfig `json:"pullConfig,omitempty"`
+	} `json:"image"`
+}
+
+type metronomeJobPullConfig struct {
+	Secret string `json:"secret"`
+}
+
+/**
+ * metronomeRequest places a request against the Metronome API. The response
+ * is returned, so callers can tell missing jobs and runs apart.
+ */
+func metronomeRequest(ctx context.Context, client *dcos.APIClient, method string, path string, body interface{}, result interface{}) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to encode request: %s", err.Error())
+		}
+	}
+
+	req, err := util.DCOSNewRequest(ctx, client, method, "/service/metronome/v1"+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create request: %s", err.Error())
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Printf("[TRACE] Metronome %s %s %s", method, req.URL.String(), util.RedactBody(path, payload))
+
+	resp, err := client.HTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp, fmt.Errorf("Unable to read response: %s", err.Error())
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, fmt.Errorf("Metronome responded with %s: %s", resp.Status, string(content))
+	}
+
+	if result == nil {
+		return resp, nil
+	}
+	if err := json.Unmarshal(content, result); err != nil {
+		return resp, fmt.Errorf("Unable to parse response: %s", err.Error())
+	}
+	return resp, nil
+}
+
+/**
+ * jobDefinition merges the extensions into the job definition
+ */
+func jobDefinition(job dcos.MetronomeV1Job, extensions metronomeJobExtensions) (map[string]interface{}, error) {
+	definition := make(map[string]interface{})
+	for _, part := range []interface{}{job, extensions} {
+		encoded, err := json.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			return nil, err
+		}
+		mergeJSONObjects(definition, decoded)
+	}
+	return definition, nil
+}
+
+/**
+ * mergeJSONObjects merges the source into the target, recursing into objects
+ * present in both
+ */
+func mergeJSONObjects(target map[string]interface{}, source map[string]interface{}) {
+	for k, v := range source {
+		sourceObject, sourceIsObject := v.(map[string]interface{})
+		targetObject, targetIsObject := target[k].(map[string]interface{})
+		if sourceIsObject && targetIsObject {
+			mergeJSONObjects(targetObject, sourceObject)
+			continue
+		}
+		target[k] = v
+	}
+}
+
+/**
+ * createJob works like the generated V1CreateJob, but sends the given
+ * definition as-is
+ */
+func createJob(ctx context.Context, client *dcos.APIClient, definition map[string]interface{}) (*http.Response, error) {
+	return metronomeRequest(ctx, client, http.MethodPost, "/jobs", definition, nil)
+}
+
+/**
+ * updateJob works like the generated V1UpdateJob, but sends the given
+ * definition as-is
+ */
+func updateJob(ctx context.Context, client *dcos.APIClient, jobID string, definition map[string]interface{}) (*http.Response, error) {
+	return metronomeRequest(ctx, client, http.MethodPut, "/jobs/"+url.PathEscape(jobID), definition, nil)
+}
+
+/**
+ * getJob works like the generated V1GetJob, but also returns the extensions
+ */
+func getJob(ctx context.Context, client *dcos.APIClient, jobID string) (dcos.MetronomeV1Job, metronomeJobExtensions, *http.Response, error) {
+	var job dcos.MetronomeV1Job
+	var extensions metronomeJobExtensions
+
+	var definition json.RawMessage
+	resp, err := metronomeRequest(ctx, client, http.MethodGet, "/jobs/"+url.PathEscape(jobID), nil, &definition)
+	if err != nil {
+		return job, extensions, resp, err
+	}
+
+	if err := json.Unmarshal(definition, &job); err != nil {
+		return job, extensions, resp, fmt.Errorf("Unable to parse job: %s", err.Error())
+	}
+	if err := json.Unmarshal(definition, &extensions); err != nil {
+		return job, extensions, resp, fmt.Errorf("Unable to parse job: %s", err.Error())
+	}
+	return job, extensions, resp, nil
+}
+
+/**
+ * getJobRuns returns the active runs of the job
+ */
+func getJobRuns(ctx context.Context, client *dcos.APIClient, jobID string) ([]metronomeJobRun, *http.Response, error) {
+	var runs []metronomeJobRun
+	resp, err := metronomeRequest(ctx, client, http.MethodGet, "/jobs/"+url.PathEscape(jobID)+"/runs", nil, &runs)
+	return runs, resp, err
+}
+
+/**
+ * getJobHistory returns the finished runs of the job
+ */
+func getJobHistory(ctx context.Context, client *dcos.APIClient, jobID string) (metronomeJobHistory, *http.Response, error) {
+	var job struct {
+		History metronomeJobHistory `json:"history"`
+	}
+	resp, err := metronomeRequest(ctx, client, http.MethodGet, "/jobs/"+url.PathEscape(jobID)+"?embed=history", nil, &job)
+	return job.History, resp, err
+}
+
+/**
+ * findJobRun returns the run of the job, looking it up in the history once it
+ * finished. Metronome stops reporting finished runs after a while, in which
+ * case the run is not found.
+ */
+func findJobRun(ctx context.Context, client *dcos.APIClient, jobID string, runID string) (metronomeJobRun, bool, *http.Response, error) {
+	var run metronomeJobRun
+	resp, err := metronomeRequest(ctx, client, http.MethodGet, "/jobs/"+url.PathEscape(jobID)+"/runs/"+url.PathEscape(runID), nil, &run)
+	if err == nil {
+		return run, true, resp, nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return run, false, resp, err
+	}
+
+	history, resp, err := getJobHistory(ctx, client, jobID)
+	if err != nil {
+		return run, false, resp, err
+	}
+
+	for _, finished := range history.SuccessfulFinishedRuns {
+		if finished.ID == runID {
+			return finished.jobRun(jobID, jobRunSuccess), true, resp, nil
+		}
+	}
+	for _, finished := range history.FailedFinishedRuns {
+		if finished.ID == runID {
+			return finished.jobRun(jobID, jobRunFailed), true, resp, nil
+		}
+	}
+
+	return run, false, resp, nil
+}
+
+/**
+ * taskIDs returns the IDs of the tasks launched by the run
+ */
+func (r metronomeJobRun) taskIDs() []string {
+	ids := make([]string, 0)
+	for _, task := range r.Tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+/**
+ * isJobRunFinished returns whether the run succeeded or failed
+ */
+func isJobRunFinished(run metronomeJobRun) bool {
+	return run.Status == jobRunSuccess || run.Status == jobRunFailed
+}
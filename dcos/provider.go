@@ -2,11 +2,16 @@ package dcos
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func Provider() terraform.ResourceProvider {
@@ -15,19 +20,45 @@ func Provider() terraform.ResourceProvider {
 			"dcos_acs_token": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("DCOS_ACS_TOKEN", ""),
+				Sensitive:   true,
 				Description: "The DC/OS access token",
 			},
 			"ssl_verify": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: sslVerifyEnvDefault,
 				Description: "Verify SSL connection",
 			},
-			"dcos_url": {
+			"ca_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: caCertificateEnvDefault,
+				Description: "PEM encoded CA certificate bundle, or a path to it, to verify the cluster with",
+			},
+			"ca_certificate_sha256": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "SHA-256 fingerprint the cluster CA certificate has to match",
+			},
+			"client_certificate": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "",
+				Description: "PEM encoded client certificate, or a path to it, to authenticate with Admin Router",
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Sensitive:   true,
+				Description: "PEM encoded private key of the client certificate, or a path to it",
+			},
+			"dcos_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("DCOS_URL", ""),
 				Description: "URL of DC/OS to use",
 			},
 			"cluster": {
@@ -36,18 +67,80 @@ func Provider() terraform.ResourceProvider {
 				Default:     "",
 				Description: "Clustername to use",
 			},
+			"cluster_profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("DCOS_CLUSTER", ""),
+				Description: "Name or ID of the attached DC/OS CLI cluster to use if no dcos_url is given",
+			},
+			"config_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("DCOS_DIR", ""),
+				Description: "DC/OS CLI configuration directory, defaults to ~/.dcos",
+			},
 			"user": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("DCOS_USERNAME", ""),
 				Description: "User name logging into the cluster",
 			},
 			"password": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Default:     "",
+				DefaultFunc: schema.EnvDefaultFunc("DCOS_PASSWORD", ""),
+				Sensitive:   true,
 				Description: "Password to login with",
 			},
+			"service_account_uid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "ID of the service account logging into the cluster",
+			},
+			"service_account_private_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Sensitive:   true,
+				Description: "The PEM encoded private key of the service account or the service account secret JSON",
+			},
+			"retry_max": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     3,
+				Description: "Maximum number of retries of a request failing with a transient error",
+			},
+			"retry_wait_min": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1,
+				Description: "Seconds to wait before the first retry, doubled on every following retry",
+			},
+			"retry_wait_max": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     30,
+				Description: "Maximum seconds to wait between retries",
+			},
+			"retry_status_codes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "HTTP status codes, or classes like 5xx, to retry. Defaults to 502 and 503",
+			},
+			"rate_limit": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Default:     0,
+				Description: "Maximum number of requests per second placed against the cluster, 0 disables the limit",
+			},
+			"http_logging": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_LOG_DCOS_HTTP", false),
+				Description: "Log every HTTP request and response, with credentials and secrets redacted",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"dcos_security_cluster_saml": resourceDcosSecurityClusterSAML(),
@@ -64,35 +157,65 @@ func Provider() terraform.ResourceProvider {
 
 			"dcos_job":          resourceDcosJob(),
 			"dcos_job_schedule": resourceDcosJobSchedule(),
+			"dcos_job_run":      resourceDcosJobRun(),
 			"dcos_package":      resourceDcosPackage(),
 			"dcos_package_repo": resourceDcosPackageRepo(),
 
 			"dcos_edgelb_v2_pool": resourceDcosEdgeLBV2Pool(),
 
-			"dcos_marathon_app": resourceDcosMarathonApp(),
-			"dcos_marathon_pod": resourceDcosMarathonPod(),
+			"dcos_marathon_app":   resourceDcosMarathonApp(),
+			"dcos_marathon_group": resourceDcosMarathonGroup(),
+			"dcos_marathon_pod":   resourceDcosMarathonPod(),
 
 			"dcos_service_http_request": resourceDcosServiceHttpRequest(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"dcos_base_url":        dataSourceDcosBaseURL(),
 			"dcos_job":             dataSourceDcosJob(),
+			"dcos_job_runs":        dataSourceDcosJobRuns(),
 			"dcos_package_config":  dataSourceDcosPackageConfig(),
 			"dcos_package_version": dataSourceDcosPackageVersion(),
 			"dcos_service":         dataSourceDcosService(),
 			"dcos_token":           dataSourceDcosToken(),
 			"dcos_version":         dataSourceDcosVersion(),
 
+			"dcos_marathon_app_tasks": dataSourceDcosMarathonAppTasks(),
+
 			"dcos_security_secret_service_account_secret": dataSourceDcosServiceAccountSecret(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
 }
 
+// sslVerifyEnvDefault reads `ssl_verify` from DCOS_SSL_VERIFY, which like for
+// the DC/OS CLI is either true, false or the path to a CA bundle to verify with
+func sslVerifyEnvDefault() (interface{}, error) {
+	value := os.Getenv("DCOS_SSL_VERIFY")
+	if value == "" {
+		return false, nil
+	}
+	if verify, err := strconv.ParseBool(value); err == nil {
+		return verify, nil
+	}
+	if _, err := os.Stat(value); err != nil {
+		return nil, fmt.Errorf("DCOS_SSL_VERIFY must be true, false or the path to a CA bundle: %s", err.Error())
+	}
+	return true, nil
+}
+
+// caCertificateEnvDefault reads `ca_certificate` from DCOS_SSL_VERIFY if it
+// holds the path to a CA bundle
+func caCertificateEnvDefault() (interface{}, error) {
+	value := os.Getenv("DCOS_SSL_VERIFY")
+	if _, err := strconv.ParseBool(value); value == "" || err == nil {
+		return "", nil
+	}
+	return value, nil
+}
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	var config *dcos.Config
-	var iamLogin dcos.IamLoginObject
-	var login bool = false
+	var loginObject func() (dcos.IamLoginObject, error)
 	var err error
 
 	// Configure custom cluster URL
@@ -101,31 +224,60 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		config = dcos.NewConfig(nil)
 		config.SetURL(dcosURL)
 
-		// Require a log-in username
-		iamLogin.Uid = d.Get("user").(string)
-		if iamLogin.Uid == "" {
-			return nil, fmt.Errorf("Missing required 'user' field")
-		}
-
-		// Populate the IAM Login object based on the arguments given
-		dcosACSToken := d.Get("dcos_acs_token").(string)
-		if dcosACSToken == "" {
-			loginPass := d.Get("password").(string)
-			if loginPass == "" {
-				return nil, fmt.Errorf("You must either provide a 'dcos_acs_token' or a 'password' field")
+		serviceAccountUID := d.Get("service_account_uid").(string)
+		serviceAccountKey := d.Get("service_account_private_key").(string)
+		if serviceAccountKey != "" {
+			// Sign a fresh login token with the service account private key on every login
+			loginObject = func() (dcos.IamLoginObject, error) {
+				return serviceAccountLoginObject(serviceAccountUID, serviceAccountKey)
+			}
+		} else if d.Get("user").(string) == "" {
+			// Without a username the token is used as-is
+			dcosACSToken := d.Get("dcos_acs_token").(string)
+			if dcosACSToken == "" {
+				return nil, fmt.Errorf("Missing required 'user', 'dcos_acs_token' or 'service_account_private_key' field")
 			}
-			iamLogin.Password = loginPass
+			config.SetACSToken(dcosACSToken)
 		} else {
-			iamLogin.Token = dcosACSToken
-		}
+			var iamLogin dcos.IamLoginObject
+
+			// Populate the IAM Login object based on the arguments given
+			iamLogin.Uid = d.Get("user").(string)
+			dcosACSToken := d.Get("dcos_acs_token").(string)
+			if dcosACSToken == "" {
+				loginPass := d.Get("password").(string)
+				if loginPass == "" {
+					return nil, fmt.Errorf("You must either provide a 'dcos_acs_token' or a 'password' field")
+				}
+				iamLogin.Password = loginPass
+			} else {
+				iamLogin.Token = dcosACSToken
+			}
 
-		// Login after we have the client
-		login = true
+			loginObject = func() (dcos.IamLoginObject, error) {
+				return iamLogin, nil
+			}
+		}
 	} else {
-		// Get current config
-		config, err = dcos.NewConfigManager(nil).Current()
-		if err != nil {
-			return nil, fmt.Errorf("Unable to get default configuration: %s", err.Error())
+		var configDirOpt dcos.ConfigManagerOpt
+		if configDir := d.Get("config_dir").(string); configDir != "" {
+			configDirOpt = dcos.ConfigDirOpt(configDir)
+		}
+		configManager := dcos.NewConfigManager(configDirOpt)
+
+		clusterProfile := d.Get("cluster_profile").(string)
+		if clusterProfile != "" {
+			// Get the requested cluster config
+			config, err = configManager.Find(clusterProfile, false)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to find cluster profile '%s': %s", clusterProfile, err.Error())
+			}
+		} else {
+			// Get current config
+			config, err = configManager.Current()
+			if err != nil {
+				return nil, fmt.Errorf("Unable to get default configuration: %s", err.Error())
+			}
 		}
 	}
 
@@ -137,6 +289,12 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		config.SetTLS(tls)
 	}
 
+	// Configure custom CA, CA pinning and client certificates
+	tlsConfig, err := providerTLSConfig(d, config)
+	if err != nil {
+		return nil, err
+	}
+
 	// Change the name of the cluster if requested
 	clusterName := d.Get("cluster").(string)
 	if clusterName != "" {
@@ -148,17 +306,182 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if tlsConfig != nil {
+		err = util.SetTLSClientConfig(client, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Log the requests on the wire, including every retry
+	if d.Get("http_logging").(bool) {
+		err = util.InstallLoggingTransport(client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Retry transient errors of all requests placed with the client
+	retryStatusCodes, _ := util.InterfaceSliceString(d.Get("retry_status_codes").([]interface{}))
+	retryTransport, err := util.NewRetryTransport(
+		d.Get("retry_max").(int),
+		time.Duration(d.Get("retry_wait_min").(int))*time.Second,
+		time.Duration(d.Get("retry_wait_max").(int))*time.Second,
+		retryStatusCodes,
+		d.Get("rate_limit").(float64),
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = util.InstallRetryTransport(client, retryTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	if loginObject != nil {
+		login := func(ctx context.Context) (string, error) {
+			iamLogin, err := loginObject()
+			if err != nil {
+				return "", err
+			}
+			authToken, _, err := client.IAM.Login(ctx, iamLogin)
+			if err != nil {
+				return "", err
+			}
+			return authToken.Token, nil
+		}
 
-	if login {
 		// Login and obtain an ACS token
-		authToken, _, err := client.IAM.Login(context.TODO(), iamLogin)
+		token, err := login(context.TODO())
 		if err != nil {
 			return nil, fmt.Errorf("Unable to authenticate: %s", err.Error())
 		}
 
 		// Update configuration object (it's passed by reference)
-		config.SetACSToken(authToken.Token)
+		config.SetACSToken(token)
+
+		// Keep the credentials around to log in again once the token expires
+		_, err = util.InstallAuthTransport(client, token, login)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return client, err
 }
+
+// providerTLSConfig creates the TLS configuration for a custom CA bundle, a
+// pinned cluster CA or client certificates. It returns nil if none is given.
+func providerTLSConfig(d *schema.ResourceData, config *dcos.Config) (*tls.Config, error) {
+	var caPEM []byte
+	var err error
+
+	caCertificate := d.Get("ca_certificate").(string)
+	caFingerprint := d.Get("ca_certificate_sha256").(string)
+	clientCertificate := d.Get("client_certificate").(string)
+	clientKey := d.Get("client_key").(string)
+
+	if caCertificate == "" && caFingerprint == "" && clientCertificate == "" {
+		return nil, nil
+	}
+
+	tlsSettings := config.TLS()
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: tlsSettings.Insecure,
+		RootCAs:            tlsSettings.RootCAs,
+	}
+
+	if caCertificate != "" {
+		caPEM, err = util.ReadPEM(caCertificate)
+		if err != nil {
+			return nil, err
+		}
+		if !util.IsPEM(caCertificate) {
+			tlsSettings.RootCAsPath = caCertificate
+		}
+	}
+
+	// Without a CA bundle, trust the cluster CA if it matches the fingerprint
+	if caFingerprint != "" {
+		if caPEM == nil {
+			caPEM, err = util.FetchClusterCA(config.URL())
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = util.VerifyCertificateFingerprint(caPEM, caFingerprint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A known CA always enables verification, regardless of `ssl_verify`
+	if caPEM != nil {
+		pool, err := util.NewCertPool(caPEM)
+		if err != nil {
+			return nil, err
+		}
+		tlsSettings.Insecure = false
+		tlsSettings.RootCAs = pool
+		config.SetTLS(tlsSettings)
+
+		tlsConfig.InsecureSkipVerify = false
+		tlsConfig.RootCAs = pool
+	}
+
+	if clientCertificate != "" {
+		if clientKey == "" {
+			return nil, fmt.Errorf("Missing required 'client_key' field")
+		}
+		certPEM, err := util.ReadPEM(clientCertificate)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := util.ReadPEM(clientKey)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load client certificate: %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// serviceAccountLoginObject creates the IAM login object for a service account.
+// The key can either be a PEM encoded private key or the service account secret
+// JSON, in which case the uid is taken from the secret if not given explicitly.
+func serviceAccountLoginObject(uid string, key string) (dcos.IamLoginObject, error) {
+	var iamLogin dcos.IamLoginObject
+
+	if util.IsServiceAccountSecret(key) {
+		sa, err := util.ParseServiceAccountSecret(key)
+		if err != nil {
+			return iamLogin, err
+		}
+		if uid == "" {
+			uid = sa.UID
+		}
+		key = sa.PrivateKey
+	}
+	if uid == "" {
+		return iamLogin, fmt.Errorf("Missing required 'service_account_uid' field")
+	}
+
+	pKey, err := util.ParseRSAPrivateKey(key)
+	if err != nil {
+		return iamLogin, err
+	}
+
+	token, err := util.ServiceAccountLoginToken(uid, pKey, time.Now())
+	if err != nil {
+		return iamLogin, err
+	}
+
+	iamLogin.Uid = uid
+	iamLogin.Token = token
+	return iamLogin, nil
+}
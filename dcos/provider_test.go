@@ -1,9 +1,18 @@
 package dcos
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/dcos/client-go/dcos"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
 )
 
 func TestProvider(t *testing.T) {
@@ -11,3 +20,266 @@ func TestProvider(t *testing.T) {
 		t.Fatalf("err: %s", err)
 	}
 }
+
+/**
+ * testUnitProviders returns the providers for unit tests running against a
+ * testserver.Server
+ */
+func testUnitProviders() map[string]terraform.ResourceProvider {
+	return map[string]terraform.ResourceProvider{
+		"dcos": Provider(),
+	}
+}
+
+/**
+ * Test that requests failing with an expired token log in again and are
+ * replayed once
+ */
+func TestProvider_expiredToken(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	create := "PUT /secrets/v1/secret/default/test/secret"
+	expired := false
+	server.OnRequest = func(request string) {
+		if request == create && !expired {
+			expired = true
+			server.ExpireTokens()
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_security_secret" "test" {
+  path  = "test/secret"
+  value = "value"
+}
+`,
+				Check: func(*terraform.State) error {
+					if !expired {
+						return fmt.Errorf("The secret was not created with %s", create)
+					}
+					if value, ok := server.Secret("default", "test/secret"); !ok || value != "value" {
+						return fmt.Errorf("Secret has value %q, expected %q", value, "value")
+					}
+
+					// Everything following the first attempt to create the secret
+					requests := server.Requests()
+					for i, request := range requests {
+						if request == create {
+							requests = requests[i+1:]
+							break
+						}
+					}
+
+					logins, replays := 0, 0
+					for _, request := range requests {
+						switch request {
+						case "POST /acs/api/v1/auth/login":
+							logins++
+						case create:
+							replays++
+						}
+					}
+					if logins != 1 || replays != 1 {
+						return fmt.Errorf("Expected 1 login and 1 replay, got %d and %d in %v", logins, replays, requests)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/**
+ * testConfigDir creates a DC/OS CLI configuration directory with a cluster
+ * for each of the given names, attaching the first one
+ */
+func testConfigDir(t *testing.T, names ...string) string {
+	dir, err := ioutil.TempDir("", "dcos")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	for i, name := range names {
+		clusterDir := filepath.Join(dir, "clusters", fmt.Sprintf("%d0000000-cluster", i+1))
+		if err := os.MkdirAll(clusterDir, 0755); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		config := fmt.Sprintf("[core]\ndcos_url = \"https://%s.example.com\"\ndcos_acs_token = \"token\"\n\n[cluster]\nname = \"%s\"\n", name, name)
+		if err := ioutil.WriteFile(filepath.Join(clusterDir, "dcos.toml"), []byte(config), 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if i == 0 {
+			if err := ioutil.WriteFile(filepath.Join(clusterDir, "attached"), nil, 0600); err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+		}
+	}
+
+	return dir
+}
+
+/**
+ * testSetenv sets the given environment variables, unsetting empty ones, and
+ * returns a function restoring them
+ */
+func testSetenv(env map[string]string) func() {
+	previous := make(map[string]*string)
+	for key, value := range env {
+		if v, ok := os.LookupEnv(key); ok {
+			previous[key] = &v
+		} else {
+			previous[key] = nil
+		}
+
+		if value == "" {
+			os.Unsetenv(key)
+		} else {
+			os.Setenv(key, value)
+		}
+	}
+
+	return func() {
+		for key, value := range previous {
+			if value == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *value)
+			}
+		}
+	}
+}
+
+/**
+ * testProviderURL configures the provider and returns the URL of the cluster
+ * it is attached to
+ */
+func testProviderURL(t *testing.T, raw map[string]interface{}) (string, error) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw)
+	client, err := providerConfigure(d)
+	if err != nil {
+		return "", err
+	}
+	config := client.(*dcos.APIClient).CurrentDCOSConfig()
+	return config.URL(), nil
+}
+
+/**
+ * Test selecting a DC/OS CLI cluster profile from a custom configuration
+ * directory
+ */
+func TestProvider_clusterProfile(t *testing.T) {
+	defer testSetenv(map[string]string{"DCOS_URL": "", "DCOS_CLUSTER": "", "DCOS_DIR": ""})()
+
+	dir := testConfigDir(t, "alpha", "beta")
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		// The attached cluster is used by default
+		{map[string]interface{}{"config_dir": dir}, "https://alpha.example.com"},
+		{map[string]interface{}{"config_dir": dir, "cluster_profile": "beta"}, "https://beta.example.com"},
+		// Profiles can be selected by a prefix of their ID as well
+		{map[string]interface{}{"config_dir": dir, "cluster_profile": "2000"}, "https://beta.example.com"},
+	} {
+		url, err := testProviderURL(t, test.raw)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %s", test.raw, err.Error())
+		} else if url != test.expected {
+			t.Errorf("Expected %s for %v, got %s", test.expected, test.raw, url)
+		}
+	}
+
+	_, err := testProviderURL(t, map[string]interface{}{"config_dir": dir, "cluster_profile": "gamma"})
+	if err == nil || !strings.Contains(err.Error(), "Unable to find cluster profile 'gamma'") {
+		t.Errorf("Expected a missing profile error, got %v", err)
+	}
+}
+
+/**
+ * Test that the provider arguments take precedence over the environment
+ */
+func TestProvider_clusterProfileEnv(t *testing.T) {
+	dir := testConfigDir(t, "alpha", "beta")
+	defer os.RemoveAll(dir)
+
+	defer testSetenv(map[string]string{"DCOS_URL": "", "DCOS_CLUSTER": "beta", "DCOS_DIR": dir})()
+
+	for _, test := range []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{}, "https://beta.example.com"},
+		{map[string]interface{}{"cluster_profile": "alpha"}, "https://alpha.example.com"},
+		{map[string]interface{}{"dcos_url": "https://url.example.com", "dcos_acs_token": "token"}, "https://url.example.com"},
+	} {
+		url, err := testProviderURL(t, test.raw)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %s", test.raw, err.Error())
+		} else if url != test.expected {
+			t.Errorf("Expected %s for %v, got %s", test.expected, test.raw, url)
+		}
+	}
+
+	// An explicit configuration directory replaces DCOS_DIR
+	other := testConfigDir(t, "gamma")
+	defer os.RemoveAll(other)
+
+	url, err := testProviderURL(t, map[string]interface{}{"config_dir": other, "cluster_profile": "gamma"})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err.Error())
+	} else if url != "https://gamma.example.com" {
+		t.Errorf("Expected https://gamma.example.com, got %s", url)
+	}
+}
+
+/**
+ * Test that DCOS_SSL_VERIFY holds either a boolean or the path to a CA bundle,
+ * like for the DC/OS CLI
+ */
+func TestProvider_sslVerifyEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dcos")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	caPath := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(caPath, []byte("ca"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	for _, test := range []struct {
+		env           string
+		sslVerify     bool
+		caCertificate string
+	}{
+		{"", false, ""},
+		{"true", true, ""},
+		{"false", false, ""},
+		{caPath, true, caPath},
+	} {
+		restore := testSetenv(map[string]string{"DCOS_SSL_VERIFY": test.env})
+		d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{})
+		restore()
+
+		if sslVerify := d.Get("ssl_verify").(bool); sslVerify != test.sslVerify {
+			t.Errorf("Expected ssl_verify %t for %q, got %t", test.sslVerify, test.env, sslVerify)
+		}
+		if caCertificate := d.Get("ca_certificate").(string); caCertificate != test.caCertificate {
+			t.Errorf("Expected ca_certificate %q for %q, got %q", test.caCertificate, test.env, caCertificate)
+		}
+	}
+
+	defer testSetenv(map[string]string{"DCOS_SSL_VERIFY": filepath.Join(dir, "missing.crt")})()
+	if _, err := sslVerifyEnvDefault(); err == nil {
+		t.Errorf("Expected an error for a missing CA bundle")
+	}
+}
@@ -884,9 +884,8 @@ func edgelbV2PoolFromSchema(d *schema.ResourceData) (dcos.EdgelbV2Pool, error) {
 	return edgelbV2Pool, nil
 }
 
-func pingEdgeLB(d *schema.ResourceData, meta interface{}) error {
+func pingEdgeLB(ctx context.Context, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
 
 	p, resp, err := client.Edgelb.Ping(ctx)
 	log.Printf("[TRACE] Edgelb.Ping - p: %s, resp: %v", p, resp)
@@ -894,9 +893,9 @@ func pingEdgeLB(d *schema.ResourceData, meta interface{}) error {
 	return err
 }
 
-func pingEdgeLBRetryFunc(d *schema.ResourceData, meta interface{}) func() *resource.RetryError {
+func pingEdgeLBRetryFunc(ctx context.Context, meta interface{}) func() *resource.RetryError {
 	return func() *resource.RetryError {
-		err := pingEdgeLB(d, meta)
+		err := pingEdgeLB(ctx, meta)
 		if err != nil {
 			return resource.RetryableError(err)
 		}
@@ -906,10 +905,11 @@ func pingEdgeLBRetryFunc(d *schema.ResourceData, meta interface{}) func() *resou
 
 func resourceDcosEdgeLBV2PoolCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
-	if err := resource.Retry(d.Timeout(schema.TimeoutCreate), pingEdgeLBRetryFunc(d, meta)); err != nil {
-		return fmt.Errorf(resourceDcosEdgeLBV2PoolPingError, err)
+	if err := util.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), pingEdgeLBRetryFunc(ctx, meta)); err != nil {
+		return util.StepError(ctx, "pinging EdgeLB", fmt.Errorf(resourceDcosEdgeLBV2PoolPingError, err))
 	}
 
 	edgelbV2Pool, err := edgelbV2PoolFromSchema(d)
@@ -927,9 +927,9 @@ func resourceDcosEdgeLBV2PoolCreate(d *schema.ResourceData, meta interface{}) er
 			log.Printf("[ERROR] Edgelb.V2CreatePool - ==========BODY=======%s==========BODY=======", string(apiError.Body()))
 		}
 
-		if resp != nil && resp.StatusCode != http.StatusInternalServerError {
+		if resp == nil || resp.StatusCode != http.StatusInternalServerError {
 			// DCOS-59682 we try read if we face an internal server errror
-			return err
+			return util.StepError(ctx, "writing pool "+d.Get("name").(string), err)
 		}
 	}
 
@@ -938,10 +938,11 @@ func resourceDcosEdgeLBV2PoolCreate(d *schema.ResourceData, meta interface{}) er
 
 func resourceDcosEdgeLBV2PoolRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
-	if err := resource.Retry(d.Timeout(schema.TimeoutCreate), pingEdgeLBRetryFunc(d, meta)); err != nil {
-		return fmt.Errorf(resourceDcosEdgeLBV2PoolPingError, err)
+	if err := util.RetryContext(ctx, d.Timeout(schema.TimeoutRead), pingEdgeLBRetryFunc(ctx, meta)); err != nil {
+		return util.StepError(ctx, "pinging EdgeLB", fmt.Errorf(resourceDcosEdgeLBV2PoolPingError, err))
 	}
 
 	var poolName string
@@ -959,7 +960,7 @@ func resourceDcosEdgeLBV2PoolRead(d *schema.ResourceData, meta interface{}) erro
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading pool "+poolName, err)
 	}
 
 	log.Printf("[TRACE] Edgelb.V2GetPool - %+v", pool)
@@ -1132,16 +1133,17 @@ func resourceDcosEdgeLBV2PoolRead(d *schema.ResourceData, meta interface{}) erro
 
 func resourceDcosEdgeLBV2PoolUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
-	if err := resource.Retry(d.Timeout(schema.TimeoutCreate), pingEdgeLBRetryFunc(d, meta)); err != nil {
-		return fmt.Errorf(resourceDcosEdgeLBV2PoolPingError, err)
+	if err := util.RetryContext(ctx, d.Timeout(schema.TimeoutUpdate), pingEdgeLBRetryFunc(ctx, meta)); err != nil {
+		return util.StepError(ctx, "pinging EdgeLB", fmt.Errorf(resourceDcosEdgeLBV2PoolPingError, err))
 	}
 
 	poolName := d.Get("name").(string)
 
-	if err := pingEdgeLB(d, meta); err != nil {
-		return err
+	if err := pingEdgeLB(ctx, meta); err != nil {
+		return util.StepError(ctx, "pinging EdgeLB", err)
 	}
 
 	edgelbV2Pool, err := edgelbV2PoolFromSchema(d)
@@ -1159,9 +1161,9 @@ func resourceDcosEdgeLBV2PoolUpdate(d *schema.ResourceData, meta interface{}) er
 			log.Printf("[ERROR] Edgelb.V2UpdatePool - ==========BODY=======%s==========BODY=======", string(apiError.Body()))
 		}
 
-		if resp != nil && resp.StatusCode != http.StatusInternalServerError {
+		if resp == nil || resp.StatusCode != http.StatusInternalServerError {
 			// DCOS-59682 we try read if we face an internal server errror
-			return err
+			return util.StepError(ctx, "writing pool "+d.Get("name").(string), err)
 		}
 	}
 
@@ -1170,10 +1172,11 @@ func resourceDcosEdgeLBV2PoolUpdate(d *schema.ResourceData, meta interface{}) er
 
 func resourceDcosEdgeLBV2PoolDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
-	if err := resource.Retry(d.Timeout(schema.TimeoutCreate), pingEdgeLBRetryFunc(d, meta)); err != nil {
-		return fmt.Errorf(resourceDcosEdgeLBV2PoolPingError, err)
+	if err := util.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), pingEdgeLBRetryFunc(ctx, meta)); err != nil {
+		return util.StepError(ctx, "pinging EdgeLB", fmt.Errorf(resourceDcosEdgeLBV2PoolPingError, err))
 	}
 
 	poolName := d.Get("name").(string)
@@ -1191,10 +1194,10 @@ func resourceDcosEdgeLBV2PoolDelete(d *schema.ResourceData, meta interface{}) er
 		if apiError, ok := err.(dcos.GenericOpenAPIError); ok {
 			log.Printf("[ERROR] Edgelb.V2DeletePool - ==========BODY=======%s==========BODY=======", string(apiError.Body()))
 		}
-		return err
+		return util.StepError(ctx, "deleting pool "+poolName, err)
 	}
 
-	return resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+	err = util.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		_, resp, err := client.Edgelb.V2GetPool(ctx, poolName)
 
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
@@ -1208,4 +1211,5 @@ func resourceDcosEdgeLBV2PoolDelete(d *schema.ResourceData, meta interface{}) er
 
 		return resource.RetryableError(fmt.Errorf("Pool %s still exists. Deleting... ", poolName))
 	})
+	return util.StepError(ctx, "waiting for pool "+poolName+" to be removed", err)
 }
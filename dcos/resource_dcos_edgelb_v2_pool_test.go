@@ -0,0 +1,57 @@
+package dcos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test creating and updating an EdgeLB pool against the test server */
+func TestDcosEdgeLBV2Pool_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := func(count int) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_edgelb_v2_pool" "test" {
+  name       = "test-pool"
+  cpus       = 0.5
+  mem        = 256
+  pool_count = %d
+}
+`, count)
+	}
+
+	checkCount := func(expected float64) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			pool, ok := server.EdgeLBPool("test-pool")
+			if !ok || pool["count"] != expected {
+				return fmt.Errorf("Pool was not deployed with %v instances: %v", expected, pool)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.EdgeLBPool("test-pool"); ok {
+				return fmt.Errorf("Pool still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(1),
+				Check:  checkCount(1),
+			},
+			{
+				Config: config(2),
+				Check:  checkCount(2),
+			},
+		},
+	})
+}
@@ -2,7 +2,6 @@ package dcos
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +11,7 @@ import (
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosJob() *schema.Resource {
@@ -20,9 +20,9 @@ func resourceDcosJob() *schema.Resource {
 		Read:   resourceDcosJobRead,
 		Update: resourceDcosJobUpdate,
 		Delete: resourceDcosJobDelete,
-		// Importer: &schema.ResourceImporter{
-		// 	State: schema.ImportStatePassthrough,
-		// },
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		SchemaVersion: 1,
 		Timeouts: &schema.ResourceTimeout{
@@ -108,13 +108,29 @@ func resourceDcosJob() *schema.Resource {
 				Optional:      true,
 				ForceNew:      false,
 				ConflictsWith: []string{"ucr"},
+				Description:   "The docker `image` to run, and whether to `force_pull_image` even if it is already downloaded on the agent.",
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateMapFields(map[string]schema.ValueType{
+					"image":            schema.TypeString,
+					"force_pull_image": schema.TypeBool,
+				}, "image"),
+			},
+			"docker_parameter": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    false,
+				Description: "Parameters passed to the docker run command.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"image": {
+						"key": {
 							Type:        schema.TypeString,
 							Required:    true,
-							ForceNew:    false,
-							Description: "The docker repository image name.",
+							Description: "The name of the parameter.",
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The value of the parameter.",
 						},
 					},
 				},
@@ -124,17 +140,48 @@ func resourceDcosJob() *schema.Resource {
 				Optional:      true,
 				ForceNew:      false,
 				ConflictsWith: []string{"docker"},
+				Description:   "The UCR `image` to run, whether to `force_pull` it even if it is already downloaded on the agent, and the `pull_secret` holding the docker config to pull it with.",
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateMapFields(map[string]schema.ValueType{
+					"image":       schema.TypeString,
+					"force_pull":  schema.TypeBool,
+					"pull_secret": schema.TypeString,
+				}, "image"),
+			},
+			"network": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    false,
+				Description: "The networks the job is attached to.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"image": {
+						"mode": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The network mode, host, container or container/bridge.",
+							ValidateFunc: validation.StringInSlice([]string{"host", "container", "container/bridge"}, false),
+						},
+						"name": {
 							Type:        schema.TypeString,
-							Required:    true,
-							ForceNew:    false,
-							Description: "The ucr repository image name.",
+							Optional:    true,
+							Description: "The name of the container network.",
+						},
+						"labels": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Labels passed to the CNI plugin of the network.",
 						},
 					},
 				},
 			},
+			"dependencies": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				ForceNew:    false,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the jobs which have to succeed before this job runs.",
+			},
 			"env": {
 				Type:        schema.TypeSet,
 				Optional:    true,
@@ -233,24 +280,24 @@ func resourceDcosJob() *schema.Resource {
 						"container_path": {
 							Type:         schema.TypeString,
 							Required:     true,
-							Description:  "The path of the volume in the container.",
-							ValidateFunc: validateRegexp("^/[^/].*$"),
+							Description:  "The path of the volume in the container. Secret files may be placed relative to the sandbox.",
+							ValidateFunc: validateRegexp("^/?[^/].*$"),
 						},
 						"host_path": {
 							Type:        schema.TypeString,
-							Required:    true,
+							Optional:    true,
 							Description: "The path of the volume on the host.",
 						},
 						"mode": {
 							Type:         schema.TypeString,
-							Required:     true,
+							Optional:     true,
 							Description:  "Possible values are RO for ReadOnly and RW for Read/Write.",
 							ValidateFunc: validation.StringInSlice([]string{"RO", "RW"}, false),
 						},
 						"secret": {
 							Type:          schema.TypeString,
 							Optional:      true,
-							Description:   "Allow for volume secrets if using UCR.",
+							Description:   "The name of the secret to place as a file at container_path, if using UCR.",
 							ConflictsWith: []string{"docker"},
 						},
 					},
@@ -276,6 +323,20 @@ func resourceDcosJob() *schema.Resource {
 				Description:  "How much disk space is needed for this job. This number does not have to be an integer, but can be a fraction.",
 				ValidateFunc: validation.IntAtLeast(0),
 			},
+			"gpus": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     false,
+				Description:  "The number of GPUs this job needs per instance.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"task_kill_grace_period_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     false,
+				Description:  "The number of seconds between escalating from SIGTERM to SIGKILL when signalling tasks to terminate.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 			"max_launch_delay": {
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -290,21 +351,30 @@ func resourceDcosJob() *schema.Resource {
 
 func resourceDcosJobCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	metronome_job, err := generateMetronomeJob(d, meta)
 	if err != nil {
 		return err
 	}
 
-	m_json, _ := json.Marshal(metronome_job)
-	log.Printf("[TRACE] Pre-create MetronomeV1Job: %+v", metronome_job)
-	log.Printf("[TRACE] MetronomeV1Job (json): %s", m_json)
+	extensions, err := generateMetronomeJobExtensions(d)
+	if err != nil {
+		return err
+	}
+
+	definition, err := jobDefinition(metronome_job, extensions)
+	if err != nil {
+		return fmt.Errorf("Unable to encode job %s: %s", metronome_job.Id, err.Error())
+	}
+
+	log.Printf("[TRACE] Metronome job definition: %s", util.PrintJSON(util.RedactJSON(definition)))
 	log.Printf("[INFO] Creating DCOS Job: %s", d.Get("name").(string))
 
-	resp_metronome_job, resp, err := client.Metronome.V1CreateJob(ctx, metronome_job)
+	resp, err := createJob(ctx, client, definition)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "creating job "+metronome_job.Id, err)
 	}
 
 	if resp.StatusCode != 201 {
@@ -312,7 +382,6 @@ func resourceDcosJobCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	log.Printf("[INFO] DCOS job successfully created (%s)", d.Get("name").(string))
-	log.Printf("[TRACE] Metronome Job Response object: %+v", resp_metronome_job)
 
 	d.SetId(d.Get("name").(string))
 
@@ -321,78 +390,129 @@ func resourceDcosJobCreate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceDcosJobRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
-	jobId := d.Get("name").(string)
+	jobId := d.Id()
 
-	job, resp, err := getDCOSJobInfo(jobId, client, ctx)
+	job, extensions, resp, err := getJob(ctx, client, jobId)
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading job "+jobId, err)
 	}
 
-	setSchemaFromJob(d, &job)
+	setSchemaFromJob(d, &job, extensions)
 
 	return nil
 }
 
-func setSchemaFromJob(d *schema.ResourceData, j *dcos.MetronomeV1Job) {
+func setSchemaFromJob(d *schema.ResourceData, j *dcos.MetronomeV1Job, extensions metronomeJobExtensions) {
 	d.Set("name", j.Id)
 	d.Set("description", j.Description)
+	d.Set("labels", j.Labels)
 
 	d.Set("user", j.Run.User)
-	// d.Set("labels", j.Run)
 	d.Set("cmd", j.Run.Cmd)
 	d.Set("args", j.Run.Args)
 
-	// maybe this needs to be a loop
-	d.Set("artifacts", j.Run.Artifacts)
-
-	// TODO missing attributes
-	if docker := j.Run.Docker; docker != nil {
-		d.Set("docker.image", docker.Image)
+	artifacts := make([]map[string]interface{}, 0)
+	for _, artifact := range j.Run.Artifacts {
+		artifacts = append(artifacts, map[string]interface{}{
+			"uri":        artifact.Uri,
+			"executable": artifact.Executable,
+			"extract":    artifact.Extract,
+			"cache":      artifact.Cache,
+		})
 	}
+	d.Set("artifacts", artifacts)
+
+	// Optional flags are only reported if set, or if they are configured
+	configuredDocker := d.Get("docker").(map[string]interface{})
+	docker := make(map[string]interface{})
+	dockerParameters := make([]map[string]interface{}, 0)
+	if j.Run.Docker != nil {
+		docker["image"] = j.Run.Docker.Image
+		if _, ok := configuredDocker["force_pull_image"]; ok || j.Run.Docker.ForcePullImage {
+			docker["force_pull_image"] = strconv.FormatBool(j.Run.Docker.ForcePullImage)
+		}
 
-	// TODO missing attributes
-	if ucr := j.Run.Ucr; ucr != nil {
-		d.Set("ucr.image", ucr.Image)
+		for _, parameter := range j.Run.Docker.Parameters {
+			dockerParameters = append(dockerParameters, map[string]interface{}{
+				"key":   parameter.Key,
+				"value": parameter.Value,
+			})
+		}
 	}
+	d.Set("docker", docker)
+	d.Set("docker_parameter", dockerParameters)
+
+	configuredUcr := d.Get("ucr").(map[string]interface{})
+	ucr := make(map[string]interface{})
+	if j.Run.Ucr != nil {
+		ucr["image"] = j.Run.Ucr.Image.Id
+		if _, ok := configuredUcr["force_pull"]; ok || j.Run.Ucr.Image.ForcePull {
+			ucr["force_pull"] = strconv.FormatBool(j.Run.Ucr.Image.ForcePull)
+		}
+		if extensions.Run.Ucr != nil && extensions.Run.Ucr.Image.PullConfig != nil {
+			ucr["pull_secret"] = extensions.Run.Ucr.Image.PullConfig.Secret
+		}
+	}
+	d.Set("ucr", ucr)
+
+	networks := make([]map[string]interface{}, 0)
+	for _, network := range extensions.Run.Networks {
+		networks = append(networks, map[string]interface{}{
+			"mode":   network.Mode,
+			"name":   network.Name,
+			"labels": network.Labels,
+		})
+	}
+	d.Set("network", networks)
 
-	if len(j.Run.Env) > 0 {
-		envRes := make([]map[string]interface{}, 0, 1)
-
-		for k, i := range j.Run.Env {
-			entry := make(map[string]interface{})
-			entry["key"] = k
-
-			switch e := i.(type) {
-			case map[string]interface{}:
-				log.Printf("[TRACE] Found map Key %s value %v, type %T", k, i, e)
-				entry["secret"] = e["secret"]
-			case string:
-				log.Printf("[TRACE] Found string Key %s value %v, type %T", k, i, e)
-				entry["value"] = e
-			default:
-				log.Printf("[WARNING] found key but no secret or value. Ignoring %v type %T", i, e)
-				continue
-			}
-
-			envRes = append(envRes, entry)
+	dependencies := make([]string, 0)
+	for _, dependency := range extensions.Dependencies {
+		dependencies = append(dependencies, dependency.ID)
+	}
+	d.Set("dependencies", dependencies)
+
+	envRes := make([]map[string]interface{}, 0)
+	for k, i := range j.Run.Env {
+		entry := make(map[string]interface{})
+		entry["key"] = k
+
+		switch e := i.(type) {
+		case map[string]interface{}:
+			log.Printf("[TRACE] Found secret for env key %s", k)
+			entry["secret"] = e["secret"]
+		case string:
+			log.Printf("[TRACE] Found value for env key %s", k)
+			entry["value"] = e
+		default:
+			log.Printf("[WARNING] found key but no secret or value. Ignoring %s of type %T", k, e)
+			continue
 		}
 
-		d.Set("env", envRes)
+		envRes = append(envRes, entry)
 	}
+	d.Set("env", envRes)
 
-	d.Set("secret", j.Run.Secrets)
+	// Metronome references the secret stores as {"source": "<path>"}
+	secrets := make(map[string]interface{})
+	for k, i := range j.Run.Secrets {
+		if secret, ok := i.(map[string]interface{}); ok {
+			secrets[k] = secret["source"]
+		}
+	}
+	d.Set("secrets", secrets)
 
-	if constraints := j.Run.Placement.Constraints; constraints != nil {
-		constraintsRes := make([]map[string]interface{}, 0)
-		for _, constraint := range *constraints {
+	constraintsRes := make([]map[string]interface{}, 0)
+	if j.Run.Placement != nil && j.Run.Placement.Constraints != nil {
+		for _, constraint := range *j.Run.Placement.Constraints {
 			c := make(map[string]interface{})
 
 			c["attribute"] = constraint.Attribute
@@ -401,27 +521,33 @@ func setSchemaFromJob(d *schema.ResourceData, j *dcos.MetronomeV1Job) {
 
 			constraintsRes = append(constraintsRes, c)
 		}
-
-		d.Set("placement_constraint", constraintsRes)
 	}
-
-	if restart := j.Run.Restart; restart != nil {
-		d.Set("restart.active_deadline_seconds", restart.ActiveDeadlineSeconds)
-		d.Set("restart.policy", restart.Policy)
-	}
-
-	if len(j.Run.Volumes) > 0 {
-		vols := make([]map[string]interface{}, 0)
-		for _, volume := range j.Run.Volumes {
-			vols = append(vols, map[string]interface{}{
-				"container_path": volume.ContainerPath,
-				"host_path":      volume.HostPath,
-				"mode":           volume.Mode,
-				"secret":         volume.Secret,
-			})
+	d.Set("placement_constraint", constraintsRes)
+
+	// Metronome reports the NEVER policy for jobs without restart settings,
+	// which is only kept if it is configured explicitly
+	restart := make(map[string]interface{})
+	if r := j.Run.Restart; r != nil {
+		_, configured := d.GetOk("restart")
+		if configured || r.Policy != "NEVER" || r.ActiveDeadlineSeconds != 0 {
+			restart["policy"] = r.Policy
+			if r.ActiveDeadlineSeconds != 0 {
+				restart["active_deadline_seconds"] = strconv.Itoa(int(r.ActiveDeadlineSeconds))
+			}
 		}
-		d.Set("volume", vols)
 	}
+	d.Set("restart", restart)
+
+	vols := make([]map[string]interface{}, 0)
+	for _, volume := range j.Run.Volumes {
+		vols = append(vols, map[string]interface{}{
+			"container_path": volume.ContainerPath,
+			"host_path":      volume.HostPath,
+			"mode":           volume.Mode,
+			"secret":         volume.Secret,
+		})
+	}
+	d.Set("volume", vols)
 
 	d.Set("cpus", j.Run.Cpus)
 
@@ -429,19 +555,24 @@ func setSchemaFromJob(d *schema.ResourceData, j *dcos.MetronomeV1Job) {
 
 	d.Set("disk", j.Run.Disk)
 
+	d.Set("gpus", j.Run.Gpus)
+
+	d.Set("task_kill_grace_period_seconds", int(j.Run.TaskKillGracePeriodSeconds))
+
 	d.Set("max_launch_delay", j.Run.MaxLaunchDelay)
 }
 
 func resourceDcosJobUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	jobId := d.Get("name").(string)
 
 	// Perform read on "name" to confirm it actually exists...
 	_, _, err := getDCOSJobInfo(jobId, client, ctx)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading job "+jobId, err)
 	}
 
 	metronome_job, err := generateMetronomeJob(d, meta)
@@ -449,14 +580,22 @@ func resourceDcosJobUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	m_json, _ := json.Marshal(metronome_job)
-	log.Printf("[TRACE] Pre-create MetronomeV1Job: %+v", metronome_job)
-	log.Printf("[TRACE] MetronomeV1Job (json): %s", m_json)
+	extensions, err := generateMetronomeJobExtensions(d)
+	if err != nil {
+		return err
+	}
+
+	definition, err := jobDefinition(metronome_job, extensions)
+	if err != nil {
+		return fmt.Errorf("Unable to encode job %s: %s", jobId, err.Error())
+	}
+
+	log.Printf("[TRACE] Metronome job definition: %s", util.PrintJSON(util.RedactJSON(definition)))
 	log.Printf("[INFO] Updating DCOS Job: %s", d.Get("name").(string))
 
-	resp_metronome_job, resp, err := client.Metronome.V1UpdateJob(ctx, jobId, metronome_job)
+	resp, err := updateJob(ctx, client, jobId, definition)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating job "+jobId, err)
 	}
 
 	if resp.StatusCode != 200 {
@@ -464,7 +603,6 @@ func resourceDcosJobUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	log.Printf("[INFO] DCOS job successfully updated (%s)", d.Get("name").(string))
-	log.Printf("[TRACE] Metronome Job Response object: %+v", resp_metronome_job)
 
 	d.SetId(d.Get("name").(string))
 
@@ -473,14 +611,15 @@ func resourceDcosJobUpdate(d *schema.ResourceData, meta interface{}) error {
 
 func resourceDcosJobDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	jobId := d.Get("name").(string)
 
 	log.Printf("[INFO] Attempting to delete (%s)", jobId)
 	resp, err := client.Metronome.V1DeleteJob(ctx, jobId)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting job "+jobId, err)
 	}
 	if resp.StatusCode != 200 {
 		return fmt.Errorf("[ERROR] Expecting response code of 200 (job deleted), but received %d", resp.StatusCode)
@@ -502,7 +641,6 @@ func getDCOSJobInfo(jobId string, client *dcos.APIClient, ctx context.Context) (
 	}
 
 	log.Printf("[INFO] DCOS job successfully retrieved (%s)", jobId)
-	log.Printf("[TRACE] Metronome Job Response object: %+v", mv1job)
 
 	return mv1job, resp, err
 }
@@ -522,6 +660,8 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 	metronome_job_run.Cpus = d.Get("cpus").(float64)
 	metronome_job_run.Mem = int64(d.Get("mem").(int))
 	metronome_job_run.MaxLaunchDelay = int32(d.Get("max_launch_delay").(int))
+	metronome_job_run.Gpus = int32(d.Get("gpus").(int))
+	metronome_job_run.TaskKillGracePeriodSeconds = float32(d.Get("task_kill_grace_period_seconds").(int))
 
 	if cmd, ok := d.GetOk("cmd"); ok {
 		metronome_job_run.Cmd = cmd.(string)
@@ -532,7 +672,9 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 	}
 
 	if args, ok := d.GetOk("args"); ok {
-		metronome_job_run.Args = args.([]string)
+		for _, arg := range args.([]interface{}) {
+			metronome_job_run.Args = append(metronome_job_run.Args, arg.(string))
+		}
 	}
 
 	if user, ok := d.GetOk("user"); ok {
@@ -560,7 +702,6 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 	if e, ok := d.GetOk("env"); ok {
 		env_config := e.(*schema.Set).List()
 
-		log.Printf("[TRACE] env (config): %+v", env_config)
 		env_map := make(map[string]interface{})
 
 		for env := range env_config {
@@ -596,10 +737,6 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 			}
 		}
 
-		log.Printf("[TRACE] env_map %+s", env_map)
-
-		env_json, _ := json.Marshal(env_map)
-		log.Printf("[TRACE] env_json %s", env_json)
 		metronome_job_run.Env = env_map
 	} else {
 		log.Printf("[TRACE] env not set, skipping")
@@ -610,18 +747,12 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 		secret_map := make(map[string]interface{})
 		config_secret := s.(map[string]interface{})
 
-		log.Printf("[TRACE] config_secret (config): %+v", config_secret)
-
 		for k, v := range config_secret {
 			secret_map[k] = map[string]string{
 				"source": v.(string),
 			}
 		}
 
-		log.Printf("[TRACE] env_secret: %+v", secret_map)
-
-		secret_map_json, _ := json.Marshal(secret_map)
-		log.Printf("[TRACE] secret_map_json %s", secret_map_json)
 		metronome_job_run.Secrets = secret_map
 	} else {
 		log.Printf("[TRACE] secrets not set, skipping")
@@ -723,11 +854,33 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 		}
 
 		metronome_job_run_docker.Image = image
+
+		// Values of the map are passed as strings
+		if force_pull_image, ok := docker_config["force_pull_image"].(string); ok {
+			var err error
+			metronome_job_run_docker.ForcePullImage, err = strconv.ParseBool(force_pull_image)
+			if err != nil {
+				return dcos.MetronomeV1Job{}, fmt.Errorf("[ERROR] docker.force_pull_image is not a bool: %s", force_pull_image)
+			}
+		}
+
+		for _, p := range d.Get("docker_parameter").([]interface{}) {
+			parameter := p.(map[string]interface{})
+			metronome_job_run_docker.Parameters = append(metronome_job_run_docker.Parameters, dcos.MetronomeV1JobRunDockerParameters{
+				Key:   parameter["key"].(string),
+				Value: parameter["value"].(string),
+			})
+		}
+
 		metronome_job_run.Docker = &metronome_job_run_docker
 	} else {
 		log.Printf("[TRACE] docker not set, skipping")
 	}
 
+	if _, ok := d.GetOk("docker_parameter"); ok && metronome_job_run.Docker == nil {
+		return dcos.MetronomeV1Job{}, fmt.Errorf("[ERROR] docker_parameter requires docker to be set")
+	}
+
 	// ucr
 	if uc, ok := d.GetOk("ucr"); ok {
 		ucr_config := uc.(map[string]interface{})
@@ -740,6 +893,15 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 
 		var metronome_job_run_ucr_image dcos.MetronomeV1JobRunUcrImage
 		metronome_job_run_ucr_image.Id = image
+
+		if force_pull, ok := ucr_config["force_pull"].(string); ok {
+			var err error
+			metronome_job_run_ucr_image.ForcePull, err = strconv.ParseBool(force_pull)
+			if err != nil {
+				return dcos.MetronomeV1Job{}, fmt.Errorf("[ERROR] ucr.force_pull is not a bool: %s", force_pull)
+			}
+		}
+
 		metronome_job_run_ucr.Image = metronome_job_run_ucr_image
 		metronome_job_run.Ucr = &metronome_job_run_ucr
 	} else {
@@ -777,18 +939,20 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 			}
 
 			tmp_secret_set := false
-			for k, v := range metronome_job_run.Secrets {
-				log.Printf("[TRACE] volume.secrets: %s:%s", k, v)
-
+			for k := range metronome_job_run.Secrets {
 				if secret == k {
 					tmp_secret_set = true
 				}
 			}
 
-			if !tmp_secret_set {
+			if secret != "" && !tmp_secret_set {
 				return dcos.MetronomeV1Job{}, fmt.Errorf("[ERROR] Expecting '%s' to be part of secrets configuration", secret)
 			}
 
+			if secret == "" && (host_path == "" || mode == "") {
+				return dcos.MetronomeV1Job{}, fmt.Errorf("[ERROR] Expecting volume %s to have either a secret, or a host_path and mode", container_path)
+			}
+
 			metronome_job_volumes = append(metronome_job_volumes, dcos.MetronomeV1JobRunVolumes{
 				ContainerPath: container_path,
 				HostPath:      host_path,
@@ -843,3 +1007,49 @@ func generateMetronomeJob(d *schema.ResourceData, meta interface{}) (dcos.Metron
 
 	return metronome_job, nil
 }
+
+/**
+ * generateMetronomeJobExtensions returns the fields of the job the generated
+ * client lacks
+ */
+func generateMetronomeJobExtensions(d *schema.ResourceData) (metronomeJobExtensions, error) {
+	var extensions metronomeJobExtensions
+
+	for _, id := range d.Get("dependencies").(*schema.Set).List() {
+		extensions.Dependencies = append(extensions.Dependencies, metronomeJobDependency{ID: id.(string)})
+	}
+
+	for _, n := range d.Get("network").([]interface{}) {
+		network := n.(map[string]interface{})
+
+		labels := make(map[string]string)
+		for k, v := range network["labels"].(map[string]interface{}) {
+			labels[k] = v.(string)
+		}
+
+		extensions.Run.Networks = append(extensions.Run.Networks, metronomeJobNetwork{
+			Mode:   network["mode"].(string),
+			Name:   network["name"].(string),
+			Labels: labels,
+		})
+	}
+
+	// Sent explicitly, as the generated client omits zero values, which would
+	// keep the previous values
+	gpus := d.Get("gpus").(int)
+	extensions.Run.Gpus = &gpus
+	taskKillGracePeriodSeconds := d.Get("task_kill_grace_period_seconds").(int)
+	extensions.Run.TaskKillGracePeriodSeconds = &taskKillGracePeriodSeconds
+
+	ucr := d.Get("ucr").(map[string]interface{})
+	if secret, ok := ucr["pull_secret"].(string); ok && secret != "" {
+		if _, ok := d.Get("secrets").(map[string]interface{})[secret]; !ok {
+			return extensions, fmt.Errorf("[ERROR] Expecting '%s' to be part of secrets configuration", secret)
+		}
+
+		extensions.Run.Ucr = &metronomeJobUcrExtensions{}
+		extensions.Run.Ucr.Image.PullConfig = &metronomeJobPullConfig{Secret: secret}
+	}
+
+	return extensions, nil
+}
@@ -0,0 +1,224 @@
+package dcos
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dcos/client-go/dcos"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
+)
+
+/**
+ * How long a started run may be missing from the active runs and the history,
+ * while Metronome is still registering it
+ */
+var jobRunStartGracePeriod = 30 * time.Second
+
+func resourceDcosJobRun() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDcosJobRunCreate,
+		Read:   resourceDcosJobRunRead,
+		Update: resourceDcosJobRunUpdate,
+		Delete: resourceDcosJobRunDelete,
+
+		SchemaVersion: 1,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"job_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the job to run",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values which start a new run when changed",
+			},
+			"wait": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Wait until the run succeeds, and fail if it fails or does not finish within the create timeout",
+			},
+			"run_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the run, eg. ACTIVE, SUCCESS or FAILED",
+			},
+			"task_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"finished_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDcosJobRunCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*dcos.APIClient)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
+
+	jobID := d.Get("job_id").(string)
+
+	log.Printf("[INFO] Starting a run of job %s", jobID)
+
+	started := time.Now()
+	run, _, err := client.Metronome.V1StartJobRun(ctx, jobID)
+	if err != nil {
+		return util.StepError(ctx, "starting a run of job "+jobID, err)
+	}
+
+	// The generated client decodes the run as a job, whose ID is the run ID
+	d.SetId(run.Id)
+
+	if !d.Get("wait").(bool) {
+		return resourceDcosJobRunRead(d, meta)
+	}
+
+	var finished metronomeJobRun
+	err = util.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		jobRun, found, resp, err := findJobRun(ctx, client, jobID, d.Id())
+		if err != nil {
+			// The run is gone together with its job
+			if ctx.Err() != nil || (resp != nil && resp.StatusCode == http.StatusNotFound) {
+				return resource.NonRetryableError(err)
+			}
+			log.Printf("[WARN] Unable to read run %s of job %s: %s", d.Id(), jobID, err.Error())
+			return resource.RetryableError(err)
+		}
+		if !found {
+			if time.Since(started) < jobRunStartGracePeriod {
+				return resource.RetryableError(fmt.Errorf("Run %s of job %s is not reported yet", d.Id(), jobID))
+			}
+			return resource.NonRetryableError(fmt.Errorf("Run %s of job %s disappeared", d.Id(), jobID))
+		}
+		if !isJobRunFinished(jobRun) {
+			return resource.RetryableError(fmt.Errorf("Run %s of job %s is %s", d.Id(), jobID, jobRun.Status))
+		}
+
+		finished = jobRun
+		return nil
+	})
+	if err != nil {
+		return util.StepError(ctx, "waiting for run "+d.Id()+" of job "+jobID, err)
+	}
+
+	if finished.Status == jobRunFailed {
+		setSchemaFromJobRun(d, finished)
+		return fmt.Errorf("Run %s of job %s failed", d.Id(), jobID)
+	}
+
+	return resourceDcosJobRunRead(d, meta)
+}
+
+func resourceDcosJobRunRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*dcos.APIClient)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	jobID := d.Get("job_id").(string)
+
+	run, found, resp, err := findJobRun(ctx, client, jobID, d.Id())
+	if err != nil {
+		// The run is gone together with its job
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return util.StepError(ctx, "reading run "+d.Id()+" of job "+jobID, err)
+	}
+
+	// Metronome only keeps the history of the latest runs. The outcome of
+	// older runs stays as last read.
+	if !found {
+		log.Printf("[WARN] Run %s of job %s is no longer reported by Metronome", d.Id(), jobID)
+		return nil
+	}
+
+	setSchemaFromJobRun(d, run)
+
+	return nil
+}
+
+func setSchemaFromJobRun(d *schema.ResourceData, run metronomeJobRun) {
+	d.Set("run_id", run.ID)
+	d.Set("status", run.Status)
+	d.Set("task_ids", run.taskIDs())
+	d.Set("created_at", run.CreatedAt)
+	d.Set("finished_at", run.CompletedAt)
+}
+
+func resourceDcosJobRunUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Only wait can change, which affects the next run
+	return resourceDcosJobRunRead(d, meta)
+}
+
+func resourceDcosJobRunDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*dcos.APIClient)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
+
+	jobID := d.Get("job_id").(string)
+
+	run, found, resp, err := findJobRun(ctx, client, jobID, d.Id())
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			d.SetId("")
+			return nil
+		}
+		return util.StepError(ctx, "reading run "+d.Id()+" of job "+jobID, err)
+	}
+
+	if !found || isJobRunFinished(run) {
+		d.SetId("")
+		return nil
+	}
+
+	log.Printf("[INFO] Stopping run %s of job %s", d.Id(), jobID)
+
+	_, err = client.Metronome.V1StopJobRunByRunId(ctx, jobID, d.Id())
+	if err != nil {
+		return util.StepError(ctx, "stopping run "+d.Id()+" of job "+jobID, err)
+	}
+
+	err = util.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		run, found, _, err := findJobRun(ctx, client, jobID, d.Id())
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if found && !isJobRunFinished(run) {
+			return resource.RetryableError(fmt.Errorf("Run %s of job %s is still %s", d.Id(), jobID, run.Status))
+		}
+		return nil
+	})
+	if err != nil {
+		return util.StepError(ctx, "waiting for run "+d.Id()+" of job "+jobID+" to stop", err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,130 @@
+package dcos
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+func testJobRunConfig(server *testserver.Server, body string) string {
+	return server.ProviderConfig() + `
+resource "dcos_job" "test" {
+  name = "test.job"
+  cmd  = "sleep 1"
+  cpus = 0.1
+  mem  = 32
+}
+` + body
+}
+
+/** Test starting a new run when the triggers change */
+func TestDcosJobRun_triggers(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := func(revision string) string {
+		return testJobRunConfig(server, fmt.Sprintf(`
+resource "dcos_job_run" "test" {
+  job_id = dcos_job.test.name
+
+  triggers = {
+    revision = %q
+  }
+}
+`, revision))
+	}
+
+	var firstRun string
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: config("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_job_run.test", "status", "SUCCESS"),
+					resource.TestCheckResourceAttr("dcos_job_run.test", "task_ids.#", "1"),
+					resource.TestCheckResourceAttrSet("dcos_job_run.test", "finished_at"),
+					func(s *terraform.State) error {
+						firstRun = s.RootModule().Resources["dcos_job_run.test"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: config("2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_job_run.test", "status", "SUCCESS"),
+					func(s *terraform.State) error {
+						if s.RootModule().Resources["dcos_job_run.test"].Primary.ID == firstRun {
+							return fmt.Errorf("No new run was started")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+/** Test failing when the run fails */
+func TestDcosJobRun_failure(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	server.FailJobRuns("test.job", true)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: testJobRunConfig(server, `
+resource "dcos_job_run" "test" {
+  job_id = dcos_job.test.name
+}
+`),
+				ExpectError: regexp.MustCompile(`Run \S+ of job test.job failed`),
+			},
+		},
+	})
+}
+
+/** Test stopping an active run on destroy */
+func TestDcosJobRun_stopOnDestroy(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	server.HoldJobRuns("test.job", true)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if runs := server.ActiveJobRuns("test.job"); len(runs) > 0 {
+				return fmt.Errorf("Runs still active: %v", runs)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testJobRunConfig(server, `
+resource "dcos_job_run" "test" {
+  job_id = dcos_job.test.name
+  wait   = false
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_job_run.test", "status", "ACTIVE"),
+					func(*terraform.State) error {
+						if runs := server.ActiveJobRuns("test.job"); len(runs) != 1 {
+							return fmt.Errorf("Expected one active run, got %v", runs)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
@@ -1,14 +1,16 @@
 package dcos
 
 import (
-	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosJobSchedule() *schema.Resource {
@@ -17,9 +19,10 @@ func resourceDcosJobSchedule() *schema.Resource {
 		Read:   resourceDcosJobScheduleRead,
 		Update: resourceDcosJobScheduleUpdate,
 		Delete: resourceDcosJobScheduleDelete,
-		// Importer: &schema.ResourceImporter{
-		// 	State: schema.ImportStatePassthrough,
-		// },
+		Importer: &schema.ResourceImporter{
+			State: resourceDcosJobScheduleImport,
+		},
+		CustomizeDiff: resourceDcosJobScheduleCustomizeDiff,
 
 		SchemaVersion: 1,
 		Timeouts: &schema.ResourceTimeout{
@@ -47,7 +50,7 @@ func resourceDcosJobSchedule() *schema.Resource {
 				Required:     true,
 				ForceNew:     false,
 				Description:  "Cron based schedule string",
-				ValidateFunc: validateRegexp("^[0-9\\*\\/,]{1,5} [0-9\\*\\/,\\-]{1,15} [0-9\\*\\/,]{1,5} [0-9\\*\\/,]{1,5} [0-9\\*\\/,]{1,5}$"),
+				ValidateFunc: validateCron,
 			},
 			"concurrency_policy": {
 				Type:         schema.TypeString,
@@ -56,6 +59,10 @@ func resourceDcosJobSchedule() *schema.Resource {
 				Default:      "",
 				Description:  "Defines the behavior if a job is started, before the current job has finished. ALLOW will launch a new job, even if there is an existing run.",
 				ValidateFunc: validation.StringInSlice([]string{"ALLOW", ""}, false),
+				// Metronome reports ALLOW if no policy was given
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return (old == "" || old == "ALLOW") && (new == "" || new == "ALLOW")
+				},
 			},
 			"enabled": {
 				Type:        schema.TypeBool,
@@ -77,7 +84,26 @@ func resourceDcosJobSchedule() *schema.Resource {
 				Optional:     true,
 				ForceNew:     false,
 				Description:  "IANA based time zone string. See http://www.iana.org/time-zones for a list of available time zones.",
-				ValidateFunc: validateRegexp("^[a-zA-Z]+/?[a-zA-Z]+$"),
+				ValidateFunc: validateTimezone,
+				// Metronome reports UTC if no time zone was given
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return (old == "" || old == "UTC") && (new == "" || new == "UTC")
+				},
+			},
+			// Not prefixed with next_runs, as setting them in CustomizeDiff
+			// clears all attributes sharing the prefix
+			"next_run_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				Description:  "The number of upcoming fire times listed in next_runs.",
+				ValidateFunc: validation.IntBetween(0, 100),
+			},
+			"next_runs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The upcoming fire times of the schedule in its time zone.",
 			},
 		},
 	}
@@ -85,7 +111,8 @@ func resourceDcosJobSchedule() *schema.Resource {
 
 func resourceDcosJobScheduleCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	var metronome_job_schedule dcos.MetronomeV1JobSchedule
 
@@ -115,7 +142,7 @@ func resourceDcosJobScheduleCreate(d *schema.ResourceData, meta interface{}) err
 
 	sched_struct, resp, err := client.Metronome.V1CreateJobSchedules(ctx, jobId, metronome_job_schedule)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "creating schedule "+scheduleId, err)
 	}
 
 	log.Printf("[TRACE] schedule struct: %+v", sched_struct)
@@ -131,14 +158,22 @@ func resourceDcosJobScheduleCreate(d *schema.ResourceData, meta interface{}) err
 
 func resourceDcosJobScheduleRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	scheduleId := d.Get("name").(string)
 	jobId := d.Get("dcos_job_id").(string)
 
 	job_schedule, resp, err := client.Metronome.V1GetJobSchedulesByScheduleId(ctx, jobId, scheduleId)
+
+	// The schedule is gone, or the job it belonged to
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		d.SetId("")
+		return nil
+	}
+
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading schedule "+scheduleId, err)
 	}
 
 	log.Printf("[TRACE] job_schedule (read): %+v", job_schedule)
@@ -147,12 +182,93 @@ func resourceDcosJobScheduleRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("[ERROR] Expecting response code of 200 (schedule retreived), but received %d", resp.StatusCode)
 	}
 
+	d.Set("cron", job_schedule.Cron)
+	d.Set("concurrency_policy", job_schedule.ConcurrencyPolicy)
+	d.Set("enabled", job_schedule.Enabled)
+	d.Set("starting_deadline_seconds", job_schedule.StartingDeadlineSeconds)
+	d.Set("timezone", job_schedule.Timezone)
+
+	nextRuns, err := jobScheduleNextRuns(job_schedule.Cron, job_schedule.Timezone, d.Get("next_run_count").(int), time.Now())
+	if err != nil {
+		log.Printf("[WARN] Unable to compute the next runs of schedule %s: %s", scheduleId, err.Error())
+		nextRuns = []string{}
+	}
+	d.Set("next_runs", nextRuns)
+
 	return nil
 }
 
+/**
+ * resourceDcosJobScheduleCustomizeDiff previews the next runs when the schedule
+ * changes. Otherwise the next runs recomputed by the last refresh are kept, as
+ * a preview taken at plan time would show a change on every plan.
+ */
+func resourceDcosJobScheduleCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("cron") && !d.HasChange("timezone") && !d.HasChange("next_run_count") {
+		return nil
+	}
+
+	if !d.NewValueKnown("cron") || !d.NewValueKnown("timezone") || !d.NewValueKnown("next_run_count") {
+		return d.SetNewComputed("next_runs")
+	}
+
+	nextRuns, err := jobScheduleNextRuns(d.Get("cron").(string), d.Get("timezone").(string), d.Get("next_run_count").(int), time.Now())
+	if err != nil {
+		return err
+	}
+	return d.SetNew("next_runs", nextRuns)
+}
+
+/**
+ * jobScheduleNextRuns returns the next count fire times of the cron expression
+ * after now, in the given time zone or UTC
+ */
+func jobScheduleNextRuns(cron string, timezone string, count int, now time.Time) ([]string, error) {
+	schedule, err := util.ParseCron(cron)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse cron expression %q: %s", cron, err.Error())
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load time zone %q: %s", timezone, err.Error())
+	}
+
+	nextRuns := make([]string, 0)
+	t := now.In(loc)
+	for len(nextRuns) < count {
+		t = schedule.Next(t)
+		if t.IsZero() {
+			break
+		}
+		nextRuns = append(nextRuns, t.Format(time.RFC3339))
+	}
+	return nextRuns, nil
+}
+
+/**
+ * resourceDcosJobScheduleImport imports schedules by <job-id>/<schedule-id>
+ */
+func resourceDcosJobScheduleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("Invalid import ID %q, expecting <job_id>/<schedule_id>", d.Id())
+	}
+
+	d.Set("dcos_job_id", parts[0])
+	d.Set("name", parts[1])
+	d.Set("next_run_count", 5)
+	d.SetId(parts[1])
+
+	log.Printf("[TRACE] Importing schedule %s of job %s", parts[1], parts[0])
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceDcosJobScheduleUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	var metronome_job_schedule dcos.MetronomeV1JobSchedule
 
@@ -180,7 +296,7 @@ func resourceDcosJobScheduleUpdate(d *schema.ResourceData, meta interface{}) err
 
 	_, err := client.Metronome.V1PutJobSchedulesByScheduleId(ctx, jobId, scheduleId, metronome_job_schedule)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating schedule "+scheduleId, err)
 	}
 
 	d.SetId(scheduleId)
@@ -190,14 +306,15 @@ func resourceDcosJobScheduleUpdate(d *schema.ResourceData, meta interface{}) err
 
 func resourceDcosJobScheduleDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	scheduleId := d.Get("name").(string)
 	jobId := d.Get("dcos_job_id").(string)
 
 	_, err := client.Metronome.V1DeleteJobSchedulesByScheduleId(ctx, jobId, scheduleId)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting schedule "+scheduleId, err)
 	}
 
 	d.SetId("")
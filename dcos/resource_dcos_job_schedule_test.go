@@ -0,0 +1,79 @@
+package dcos
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+func testJobScheduleConfig(server *testserver.Server, cron string, timezone string) string {
+	return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_job" "test" {
+  name = "test.job"
+  cmd  = "sleep 10"
+  cpus = 0.1
+  mem  = 32
+}
+
+resource "dcos_job_schedule" "test" {
+  dcos_job_id     = dcos_job.test.name
+  name            = "nightly"
+  cron            = %q
+  timezone        = %q
+  next_run_count = 3
+}
+`, cron, timezone)
+}
+
+/** Test rejecting invalid cron expressions and time zones when planning */
+func TestDcosJobSchedule_validation(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testJobScheduleConfig(server, "0 25 * * *", "UTC"),
+				ExpectError: regexp.MustCompile(`not a valid cron expression: Value 25 of hour field is out of range`),
+			},
+			{
+				Config:      testJobScheduleConfig(server, "0 2 * * *", "Europe/Atlantis"),
+				ExpectError: regexp.MustCompile(`not a known IANA time zone`),
+			},
+		},
+	})
+
+	if requests := server.Requests(); len(requests) > 0 {
+		t.Errorf("Expected no requests, got %v", requests)
+	}
+}
+
+/** Test listing the next runs in the time zone of the schedule */
+func TestDcosJobSchedule_nextRuns(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: testJobScheduleConfig(server, "30 2 * * MON-FRI", "America/Argentina/Buenos_Aires"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_job_schedule.test", "next_runs.#", "3"),
+					resource.TestMatchResourceAttr("dcos_job_schedule.test", "next_runs.0", regexp.MustCompile(`T02:30:00-03:00$`)),
+				),
+			},
+			{
+				Config: testJobScheduleConfig(server, "0 12 1 * *", "Asia/Tokyo"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_job_schedule.test", "next_runs.#", "3"),
+					resource.TestMatchResourceAttr("dcos_job_schedule.test", "next_runs.2", regexp.MustCompile(`-01T12:00:00\+09:00$`)),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,343 @@
+package dcos
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test creating a job with a schedule against the test server */
+func TestDcosJob_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := func(cmd string) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_job" "test" {
+  name = "test.job"
+  cmd  = %q
+  cpus = 0.1
+  mem  = 32
+}
+
+resource "dcos_job_schedule" "test" {
+  dcos_job_id = dcos_job.test.name
+  name        = "nightly"
+  cron        = "0 2 * * *"
+}
+`, cmd)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.Job("test.job"); ok {
+				return fmt.Errorf("Job still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("sleep 10"),
+				Check: func(*terraform.State) error {
+					if _, ok := server.JobSchedule("test.job", "nightly"); !ok {
+						return fmt.Errorf("Schedule was not created")
+					}
+					return nil
+				},
+			},
+			{
+				Config: config("sleep 20"),
+				Check: func(*terraform.State) error {
+					job, _ := server.Job("test.job")
+					run, _ := job["run"].(map[string]interface{})
+					if run["cmd"] != "sleep 20" {
+						return fmt.Errorf("Job was not updated: %v", job)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/** Test importing a job using all supported fields, and its schedule */
+func TestDcosJob_import(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := server.ProviderConfig() + `
+resource "dcos_job" "test" {
+  name        = "test.job"
+  description = "Imported job"
+  user        = "nobody"
+  cmd         = "./backup.sh"
+  cpus        = 0.5
+  mem         = 64
+  disk        = 128
+
+  labels = {
+    team = "storage"
+  }
+
+  artifacts {
+    uri        = "https://example.com/backup.tgz"
+    extract    = true
+    executable = false
+    cache      = true
+  }
+
+  docker = {
+    image = "busybox:latest"
+  }
+
+  env {
+    key   = "TARGET"
+    value = "s3://backups"
+  }
+
+  env {
+    key    = "PASSWORD"
+    secret = "password"
+  }
+
+  secrets = {
+    password = "storage/password"
+  }
+
+  placement_constraint {
+    attribute = "hostname"
+    operator  = "LIKE"
+    value     = "agent-.*"
+  }
+
+  restart = {
+    policy                  = "ON_FAILURE"
+    active_deadline_seconds = 300
+  }
+
+  volume {
+    container_path = "/data"
+    host_path      = "/mnt/data"
+    mode           = "RW"
+  }
+}
+
+resource "dcos_job_schedule" "test" {
+  dcos_job_id        = dcos_job.test.name
+  name               = "nightly"
+  cron               = "0 2 * * *"
+  concurrency_policy = "ALLOW"
+  timezone           = "Europe/Berlin"
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config:            config,
+				ResourceName:      "dcos_job.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:            config,
+				ResourceName:      "dcos_job_schedule.test",
+				ImportState:       true,
+				ImportStateId:     "test.job/nightly",
+				ImportStateVerify: true,
+				// The next runs move on with the time of the import
+				ImportStateVerifyIgnore: []string{"next_runs"},
+			},
+		},
+	})
+}
+
+/** Test the job fields the generated Metronome client lacks */
+func TestDcosJob_specParity(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	prerequisites := server.ProviderConfig() + `
+resource "dcos_job" "prepare" {
+  name = "test.prepare"
+  cmd  = "./prepare.sh"
+  cpus = 0.1
+  mem  = 32
+}
+`
+
+	withDocker := prerequisites + `
+resource "dcos_job" "test" {
+  name                           = "test.job"
+  cmd                            = "./train.sh"
+  cpus                           = 1
+  mem                            = 1024
+  gpus                           = 2
+  task_kill_grace_period_seconds = 30
+  dependencies                   = [dcos_job.prepare.name]
+
+  docker = {
+    image            = "tensorflow/tensorflow:latest-gpu"
+    force_pull_image = true
+  }
+
+  docker_parameter {
+    key   = "shm-size"
+    value = "1g"
+  }
+
+  network {
+    mode = "container"
+    name = "dcos"
+
+    labels = {
+      team = "ml"
+    }
+  }
+}
+`
+
+	withUcr := prerequisites + `
+resource "dcos_job" "test" {
+  name = "test.job"
+  cmd  = "./train.sh"
+  cpus = 1
+  mem  = 1024
+
+  ucr = {
+    image       = "registry.example.com/ml/train:latest"
+    force_pull  = false
+    pull_secret = "registry"
+  }
+
+  secrets = {
+    registry    = "ml/registry-config"
+    credentials = "ml/credentials"
+  }
+
+  volume {
+    container_path = "credentials.json"
+    secret         = "credentials"
+  }
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: withDocker,
+				Check: func(*terraform.State) error {
+					job, _ := server.Job("test.job")
+					run := job["run"].(map[string]interface{})
+					if run["gpus"] != float64(2) || run["taskKillGracePeriodSeconds"] != float64(30) {
+						return fmt.Errorf("Resources were not sent: %v", run)
+					}
+					networks := run["networks"].([]interface{})
+					if len(networks) != 1 || networks[0].(map[string]interface{})["name"] != "dcos" {
+						return fmt.Errorf("Networks were not sent: %v", run["networks"])
+					}
+					docker := run["docker"].(map[string]interface{})
+					if docker["forcePullImage"] != true || len(docker["parameters"].([]interface{})) != 1 {
+						return fmt.Errorf("Docker settings were not sent: %v", docker)
+					}
+					dependencies := job["dependencies"].([]interface{})
+					if len(dependencies) != 1 || dependencies[0].(map[string]interface{})["id"] != "test.prepare" {
+						return fmt.Errorf("Dependencies were not sent: %v", job["dependencies"])
+					}
+					return nil
+				},
+			},
+			{
+				Config:            withDocker,
+				ResourceName:      "dcos_job.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: withUcr,
+				Check: func(*terraform.State) error {
+					job, _ := server.Job("test.job")
+					run := job["run"].(map[string]interface{})
+					image := run["ucr"].(map[string]interface{})["image"].(map[string]interface{})
+					if image["pullConfig"].(map[string]interface{})["secret"] != "registry" {
+						return fmt.Errorf("Pull config was not sent: %v", image)
+					}
+					volumes := run["volumes"].([]interface{})
+					if len(volumes) != 1 || volumes[0].(map[string]interface{})["secret"] != "credentials" {
+						return fmt.Errorf("Secret volume was not sent: %v", run["volumes"])
+					}
+					if _, ok := job["dependencies"]; ok {
+						return fmt.Errorf("Dependencies were not removed: %v", job["dependencies"])
+					}
+					if run["gpus"] != float64(0) || run["taskKillGracePeriodSeconds"] != float64(0) {
+						return fmt.Errorf("Resources were not reset: %v", run)
+					}
+					return nil
+				},
+			},
+			{
+				Config:            withUcr,
+				ResourceName:      "dcos_job.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Flags turned off explicitly are indistinguishable from unset ones
+				ImportStateVerifyIgnore: []string{"ucr.%", "ucr.force_pull"},
+			},
+		},
+	})
+}
+
+/** Test that the fields of the docker and ucr maps are validated */
+func TestDcosJob_containerValidation(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := func(container string) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_job" "test" {
+  name = "test.job"
+  cmd  = "./train.sh"
+  cpus = 1
+  mem  = 1024
+
+  %s
+}
+`, container)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`docker = {
+    image      = "tensorflow/tensorflow"
+    force_pull = true
+  }`),
+				ExpectError: regexp.MustCompile(`"docker" has an unknown field "force_pull"`),
+			},
+			{
+				Config: config(`ucr = {
+    image      = "tensorflow/tensorflow"
+    force_pull = "always"
+  }`),
+				ExpectError: regexp.MustCompile(`"ucr.force_pull" \("always"\) must be true or false`),
+			},
+			{
+				Config: config(`docker = {
+    force_pull_image = true
+  }`),
+				ExpectError: regexp.MustCompile(`"docker" requires the field "image"`),
+			},
+		},
+	})
+}
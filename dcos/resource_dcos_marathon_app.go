@@ -38,6 +38,8 @@ package dcos
 //
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -51,16 +53,27 @@ import (
 	"github.com/hashicorp/terraform/helper/validation"
 
 	marathon "github.com/gambol99/go-marathon"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 var legacyStringRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
 
 func resourceDcosMarathonApp() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceDcosMarathonAppCreate,
 		Read:   resourceDcosMarathonAppRead,
 		Update: resourceDcosMarathonAppUpdate,
 		Delete: resourceDcosMarathonAppDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceDcosMarathonAppImport,
+		},
+
+		SchemaVersion: 1,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"marathon_service_url": &schema.Schema{
@@ -77,9 +90,10 @@ func resourceDcosMarathonApp() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"plan_path": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "v1/plan",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "v1/plan",
+							Description: "The endpoint of the framework reporting the status of its deployment plan",
 						},
 						"timeout": &schema.Schema{
 							Type:        schema.TypeInt,
@@ -88,13 +102,20 @@ func resourceDcosMarathonApp() *schema.Resource {
 							Description: "Timeout in seconds to wait for a framework to complete deployment",
 						},
 						"is_framework": &schema.Schema{
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  false,
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Wait until the deployment plan of the framework is complete",
 						},
 					},
 				},
 			},
+			"rollback_on_failure": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Roll back to the previous version of the app if its deployment fails or times out",
+			},
 			"accepted_resource_roles": &schema.Schema{
 				Type:     schema.TypeList,
 				Optional: true,
@@ -588,6 +609,162 @@ func resourceDcosMarathonApp() *schema.Resource {
 					},
 				},
 			},
+			"readiness_checks": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: false,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "readinessCheck",
+						},
+						"protocol": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "HTTP",
+							ValidateFunc: validation.StringInSlice([]string{"HTTP", "HTTPS"}, false),
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "/",
+						},
+						"port_name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the port definition or port mapping to check",
+						},
+						"interval_seconds": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  30,
+						},
+						"timeout_seconds": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  10,
+						},
+						"http_status_codes_for_ready": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"preserve_last_response": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+			"residency": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"task_lost_behavior": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.StringInSlice([]string{"WAIT_FOREVER", "RELAUNCH_AFTER_TIMEOUT"}, false),
+						},
+						"relaunch_escalation_timeout_seconds": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"task_kill_grace_period_seconds": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Optional: true,
+				ForceNew: false,
+			},
+			"role": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    false,
+				Description: "The Mesos role of the app. Marathon picks a default role if unset.",
+			},
+			"tty": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: false,
+			},
+			"resource_limits": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cpus": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      "Limit of cpus or \"unlimited\"",
+							ValidateFunc:     validateResourceLimit,
+							DiffSuppressFunc: suppressEqualResourceLimits,
+						},
+						"mem": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      "Limit of mem in MiB or \"unlimited\"",
+							ValidateFunc:     validateResourceLimit,
+							DiffSuppressFunc: suppressEqualResourceLimits,
+						},
+					},
+				},
+			},
+			"ip_address": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"networks"},
+				Description:   "Deprecated IP-per-task configuration, Marathon 1.5 and later translate it into networks",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"groups": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"labels": {
+							Type:     schema.TypeMap,
+							Optional: true,
+						},
+						"discovery_ports": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"number": {
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+									"protocol": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Default:  "tcp",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 			"kill_selection": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -607,23 +784,22 @@ func resourceDcosMarathonApp() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
-			// many other "computed" values haven't been added.
 		},
 	}
-}
 
-type deploymentEvent struct {
-	id    string
-	state string
+	r.Schema["definition_json"] = definitionJSONSchema(r.Schema,
+		"app_id", "marathon_service_url", "dcos_framework", "rollback_on_failure")
+
+	return r
 }
 
 type marathonConf struct {
-	config                   marathon.Config
-	Client                   marathon.Marathon
-	DefaultDeploymentTimeout time.Duration
+	config      marathon.Config
+	Client      marathon.Marathon
+	Deployments *deploymentWatcher
 }
 
-func genMarathonConf(d *schema.ResourceData, meta interface{}) (marathonConf, error) {
+func genMarathonConf(ctx context.Context, d *schema.ResourceData, meta interface{}) (marathonConf, error) {
 	client := meta.(*dcos.APIClient)
 
 	marathonConfig := marathon.NewDefaultConfig()
@@ -634,16 +810,16 @@ func genMarathonConf(d *schema.ResourceData, meta interface{}) (marathonConf, er
 	// FIXME: support mom by providing marathon path
 	marathonConfig.URL = dcosConf.URL() + "/" + strings.TrimLeft(serviceURL, "/")
 
-	marathonConfig.HTTPClient = client.HTTPClient()
+	// go-marathon does not accept contexts, so bind them to the HTTP client
+	marathonConfig.HTTPClient = util.ContextHTTPClient(ctx, client.HTTPClient())
 	marathonConfig.HTTPSSEClient = client.HTTPClient()
 
 	marathonConfig.EventsTransport = marathon.EventsTransportSSE
 
-	// FIXME: make this configurable for each app.
-	// DefaultDeploymentTimeout: time.Duration(d.Get("deployment_timeout").(int)) * time.Second
+	// Deployments are bound by the timeout of the context
 	conf := marathonConf{
-		config:                   marathonConfig,
-		DefaultDeploymentTimeout: time.Duration(600) * time.Second,
+		config:      marathonConfig,
+		Deployments: getDeploymentWatcher(client, marathonConfig.URL),
 	}
 
 	log.Printf("[TRACE] - MarathonConfig ")
@@ -661,100 +837,216 @@ func (c *marathonConf) loadAndValidate() error {
 	return err
 }
 
-func readDeploymentEvents(meta *marathon.Marathon, c chan deploymentEvent, ready chan bool) error {
-	client := *meta
-
-	EventIDs := marathon.EventIDDeploymentSuccess | marathon.EventIDDeploymentFailed
+/**
+ * waitOnSuccessfulDeployment waits until the given deployment of the target is
+ * completed
+ */
+func waitOnSuccessfulDeployment(ctx context.Context, config marathonConf, id string, target deploymentTarget) error {
+	return config.Deployments.wait(ctx, config, id, target)
+}
 
-	events, err := client.AddEventsListener(EventIDs)
+/**
+ * rollbackAppDeployment undoes a failed deployment of the app. A deployment
+ * still in progress is cancelled, which makes marathon roll it back. A finished
+ * deployment is undone by restoring the last good version of the app. The
+ * returned error describes both the failure and the outcome of the rollback.
+ */
+func rollbackAppDeployment(d *schema.ResourceData, meta interface{}, operation string, deployment *marathon.DeploymentID, previousVersion string, cause error) error {
+	appID := d.Get("app_id").(string)
+
+	// The context of the operation has probably expired, so the rollback gets
+	// a timeout of its own
+	ctx, cancel := util.TimeoutContext(d, operation)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
-		log.Fatalf("Failed to register for events, %s", err)
+		return fmt.Errorf("%s. Unable to roll back app %s: %s", cause.Error(), appID, err.Error())
+	}
+	client := config.Client
+
+	log.Printf("[INFO] Rolling back deployment %s of app %s", deployment.DeploymentID, appID)
+
+	rollback, err := client.DeleteDeployment(deployment.DeploymentID, false)
+	if err == nil {
+		err = waitOnSuccessfulDeployment(ctx, config, rollback.DeploymentID, deploymentTarget{apps: []string{appID}})
+		if err != nil {
+			return fmt.Errorf("%s. Cancelled the deployment, but its rollback did not complete: %s", cause.Error(), err.Error())
+		}
+
+		// Rolling back the creation of an app removes it
+		if previousVersion == "" {
+			d.SetId("")
+		}
+		return fmt.Errorf("%s. Cancelled the deployment and rolled back app %s", cause.Error(), appID)
+	}
+	if apiErr, ok := err.(*marathon.APIError); !ok || apiErr.ErrCode != marathon.ErrCodeNotFound {
+		return fmt.Errorf("%s. Unable to cancel the deployment: %s", cause.Error(), util.StepError(ctx, "cancelling deployment "+deployment.DeploymentID, err).Error())
 	}
-	defer client.RemoveEventsListener(events)
-	defer close(c)
-	ready <- true
 
-	for event := range events {
-		switch mEvent := event.Event.(type) {
-		case *marathon.EventDeploymentSuccess:
-			c <- deploymentEvent{mEvent.ID, event.Name}
-		case *marathon.EventDeploymentFailed:
-			c <- deploymentEvent{mEvent.ID, event.Name}
+	// The deployment has already finished. A new app has no good version to
+	// return to, so it is removed instead.
+	if previousVersion == "" {
+		removal, err := client.DeleteApplication(appID, false)
+		if err != nil {
+			return fmt.Errorf("%s. Unable to remove app %s: %s", cause.Error(), appID, util.StepError(ctx, "removing app "+appID, err).Error())
 		}
+		err = waitOnSuccessfulDeployment(ctx, config, removal.DeploymentID, deploymentTarget{apps: []string{appID}})
+		if err != nil {
+			return fmt.Errorf("%s. Removing app %s did not complete: %s", cause.Error(), appID, err.Error())
+		}
+
+		d.SetId("")
+		return fmt.Errorf("%s. Removed app %s", cause.Error(), appID)
 	}
-	return err
+
+	// Otherwise restore the last good version
+	version, err := lastGoodAppVersion(client, appID, deployment.Version, previousVersion)
+	if err != nil {
+		return fmt.Errorf("%s. Unable to roll back app %s: %s", cause.Error(), appID, util.StepError(ctx, "looking up the versions of app "+appID, err).Error())
+	}
+
+	restore, err := client.SetApplicationVersion(appID, &marathon.ApplicationVersion{Version: version})
+	if err != nil {
+		return fmt.Errorf("%s. Unable to roll back app %s to version %s: %s", cause.Error(), appID, version, util.StepError(ctx, "restoring app "+appID, err).Error())
+	}
+
+	err = waitOnSuccessfulDeployment(ctx, config, restore.DeploymentID, deploymentTarget{apps: []string{appID}})
+	if err != nil {
+		return fmt.Errorf("%s. Rolling back app %s to version %s did not complete: %s", cause.Error(), appID, version, err.Error())
+	}
+
+	return fmt.Errorf("%s. Rolled back app %s to version %s", cause.Error(), appID, version)
 }
 
-func waitOnSuccessfulDeployment(c chan deploymentEvent, id string, timeout time.Duration) error {
-	select {
-	case dEvent := <-c:
-		if dEvent.id == id {
-			switch dEvent.state {
-			case "deployment_success":
-				return nil
-			case "deployment_failed":
-				return errors.New("Received deployment_failed event from marathon")
+/**
+ * lastGoodAppVersion picks the version to roll back to after the deployment of
+ * failedVersion failed. That is the version known before the deployment if
+ * marathon still has it, or else the newest version preceding the failed one.
+ */
+func lastGoodAppVersion(client marathon.Marathon, appID string, failedVersion string, knownVersion string) (string, error) {
+	versions, err := client.ApplicationVersions(appID)
+	if err != nil {
+		return "", err
+	}
+
+	if knownVersion != "" {
+		for _, version := range versions.Versions {
+			if version == knownVersion {
+				return version, nil
 			}
 		}
-	case <-time.After(timeout):
-		return errors.New("Deployment timeout reached. Did not receive any deployment events")
 	}
+
+	// Marathon lists the versions from newest to oldest
+	for _, version := range versions.Versions {
+		if version < failedVersion {
+			return version, nil
+		}
+	}
+
+	return "", fmt.Errorf("No version preceding %s was found", failedVersion)
+}
+
+/**
+ * waitForFrameworkPlan waits until the deployment plan of an app marked as
+ * framework through `dcos_framework` is completed
+ */
+func waitForFrameworkPlan(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*dcos.APIClient)
+
+	frameworks := d.Get("dcos_framework").([]interface{})
+	if len(frameworks) == 0 || frameworks[0] == nil {
+		return nil
+	}
+
+	framework := frameworks[0].(map[string]interface{})
+	if !framework["is_framework"].(bool) {
+		return nil
+	}
+
+	appId := stripRootSlash(d.Get("app_id").(string))
+	planPath := framework["plan_path"].(string)
+	timeout := time.Duration(framework["timeout"].(int)) * time.Second
+
+	log.Printf("[TRACE] Waiting for plan %s of framework %s", planPath, appId)
+
+	err := waitForSDKPlanPath(ctx, client, appId, planPath, "COMPLETE", timeout)
+	if err != nil {
+		return util.StepError(ctx, "waiting for the plan of framework "+appId, fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error()))
+	}
+
 	return nil
 }
 
 func resourceDcosMarathonAppCreate(d *schema.ResourceData, meta interface{}) error {
-	config, err := genMarathonConf(d, meta)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
 		return err
 	}
 
-	client := config.Client
-
-	c := make(chan deploymentEvent, 100)
-	ready := make(chan bool)
-	go readDeploymentEvents(&client, c, ready)
-	select {
-	case <-ready:
-	case <-time.After(60 * time.Second):
-		return errors.New("Timeout getting an EventListener")
+	definition, _, err := appDefinitionFromResource(d)
+	if err != nil {
+		return err
 	}
 
-	application := mapResourceToApplication(d)
+	// Make sure we are listening for the deployment events before deploying
+	config.Deployments.subscribe(ctx)
 
-	application, err = client.CreateApplication(application)
+	application, err := config.createApplication(definition)
 	if err != nil {
 		log.Println("[ERROR] creating application", err)
-		return err
+		return util.StepError(ctx, "creating app "+d.Get("app_id").(string), err)
 	}
 	d.Partial(true)
 	d.SetId(application.ID)
-	err = setSchemaFieldsForApp(application, d)
-	if err != nil {
-		log.Println("[ERROR] setSchemaFieldsForApp", err)
-		return err
+	if _, ok := d.GetOk("definition_json"); !ok {
+		err = setSchemaFieldsForApp(application, d)
+		if err != nil {
+			log.Println("[ERROR] setSchemaFieldsForApp", err)
+			return err
+		}
 	}
 
 	for _, deploymentID := range application.DeploymentIDs() {
-		err = waitOnSuccessfulDeployment(c, deploymentID.DeploymentID, config.DefaultDeploymentTimeout)
+		err = waitOnSuccessfulDeployment(ctx, config, deploymentID.DeploymentID, deploymentTarget{version: application.Version, apps: []string{application.ID}})
 		if err != nil {
 			log.Println("[ERROR] waiting for application for deployment", deploymentID, err)
-			return err
+			diagnostics := collectDeploymentDiagnostics(d, meta, application.ID, false)
+			if d.Get("rollback_on_failure").(bool) {
+				err = rollbackAppDeployment(d, meta, schema.TimeoutCreate, deploymentID, "", err)
+			}
+			return withDeploymentDiagnostics(err, diagnostics)
 		}
 	}
 
+	err = waitForFrameworkPlan(ctx, d, meta)
+	if err != nil {
+		return err
+	}
+
 	d.Partial(false)
 
 	return resourceDcosMarathonAppRead(d, meta)
 }
 
 func resourceDcosMarathonAppRead(d *schema.ResourceData, meta interface{}) error {
-	config, err := genMarathonConf(d, meta)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
 		return err
 	}
-	client := config.Client
 
-	app, err := client.Application(d.Id())
+	if _, ok := d.GetOk("definition_json"); ok {
+		return readAppDefinitionJSON(ctx, d, config)
+	}
+
+	app, extensions, err := config.application(d.Id())
 
 	if err != nil {
 		// Handle a deleted app
@@ -762,7 +1054,7 @@ func resourceDcosMarathonAppRead(d *schema.ResourceData, meta interface{}) error
 			d.SetId("")
 			return nil
 		}
-		return err
+		return util.StepError(ctx, "reading app "+d.Id(), err)
 	}
 
 	if app != nil && app.ID == "" {
@@ -774,11 +1066,36 @@ func resourceDcosMarathonAppRead(d *schema.ResourceData, meta interface{}) error
 		if appErr != nil {
 			return appErr
 		}
+
+		appErr = setSchemaFieldsForAppExtensions(extensions, d)
+		if appErr != nil {
+			return appErr
+		}
 	}
 
 	return nil
 }
 
+/**
+ * resourceDcosMarathonAppImport accepts either an app ID (eg. `/group/app`) or
+ * an app ID prefixed with the marathon service URL of a Marathon-on-Marathon
+ * instance (eg. `service/marathon-user:/group/app`)
+ */
+func resourceDcosMarathonAppImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serviceURL, appID, err := parseMarathonImportID(d.Id(), "app_id")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("marathon_service_url", serviceURL)
+	d.Set("rollback_on_failure", false)
+	d.SetId(appID)
+
+	log.Printf("[TRACE] Importing app %s from %s", d.Id(), serviceURL)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func setSchemaFieldsForApp(app *marathon.Application, d *schema.ResourceData) error {
 
 	err := d.Set("app_id", app.ID)
@@ -949,13 +1266,17 @@ func setSchemaFieldsForApp(app *marathon.Application, d *schema.ResourceData) er
 		}
 
 		if container.PortMappings != nil && len(*container.PortMappings) > 0 {
+			// When importing there is no configuration to compare against, so we
+			// report all the fields Marathon has
+			_, known := d.GetOk("container.0.port_mappings.#")
+
 			portMappings := make([]map[string]interface{}, len(*container.PortMappings))
 			for idx, portMapping := range *container.PortMappings {
 				pmMap := make(map[string]interface{})
 				pmMap["container_port"] = portMapping.ContainerPort
 				pmMap["host_port"] = portMapping.HostPort
 				_, ok := d.GetOk("container.0.port_mappings." + strconv.Itoa(idx) + ".service_port")
-				if ok {
+				if ok || !known {
 					pmMap["service_port"] = portMapping.ServicePort
 				}
 
@@ -966,7 +1287,7 @@ func setSchemaFieldsForApp(app *marathon.Application, d *schema.ResourceData) er
 				}
 				pmMap["labels"] = labels
 				pmMap["name"] = portMapping.Name
-				if _, ok := d.GetOk("container.0.port_mappings." + strconv.Itoa(idx) + ".network_names.#"); ok {
+				if _, ok := d.GetOk("container.0.port_mappings." + strconv.Itoa(idx) + ".network_names.#"); ok || !known {
 					pmMap["network_names"] = portMapping.NetworkNames
 				}
 				portMappings[idx] = pmMap
@@ -1183,6 +1504,97 @@ func setSchemaFieldsForApp(app *marathon.Application, d *schema.ResourceData) er
 	}
 	d.SetPartial("unreachable_strategy")
 
+	if app.ReadinessChecks != nil && len(*app.ReadinessChecks) > 0 {
+		readinessChecks := make([]map[string]interface{}, len(*app.ReadinessChecks))
+		for idx, readinessCheck := range *app.ReadinessChecks {
+			rMap := make(map[string]interface{})
+			if readinessCheck.Name != nil {
+				rMap["name"] = *readinessCheck.Name
+			}
+			rMap["protocol"] = readinessCheck.Protocol
+			rMap["path"] = readinessCheck.Path
+			rMap["port_name"] = readinessCheck.PortName
+			rMap["interval_seconds"] = readinessCheck.IntervalSeconds
+			rMap["timeout_seconds"] = readinessCheck.TimeoutSeconds
+			if readinessCheck.HTTPStatusCodesForReady != nil {
+				rMap["http_status_codes_for_ready"] = *readinessCheck.HTTPStatusCodesForReady
+			}
+			if readinessCheck.PreserveLastResponse != nil {
+				rMap["preserve_last_response"] = *readinessCheck.PreserveLastResponse
+			}
+			readinessChecks[idx] = rMap
+		}
+
+		err := d.Set("readiness_checks", readinessChecks)
+		if err != nil {
+			return errors.New("Failed to set readiness_checks: " + err.Error())
+		}
+	} else {
+		d.Set("readiness_checks", nil)
+	}
+	d.SetPartial("readiness_checks")
+
+	if app.Residency != nil {
+		residency := map[string]interface{}{
+			"task_lost_behavior":                  string(app.Residency.TaskLostBehavior),
+			"relaunch_escalation_timeout_seconds": app.Residency.RelaunchEscalationTimeoutSeconds,
+		}
+		err := d.Set("residency", []interface{}{residency})
+		if err != nil {
+			return errors.New("Failed to set residency: " + err.Error())
+		}
+	} else {
+		d.Set("residency", nil)
+	}
+	d.SetPartial("residency")
+
+	err = d.Set("task_kill_grace_period_seconds", app.TaskKillGracePeriodSeconds)
+	if err != nil {
+		return errors.New("Failed to set task_kill_grace_period_seconds: " + err.Error())
+	}
+	d.SetPartial("task_kill_grace_period_seconds")
+
+	err = d.Set("role", app.Role)
+	if err != nil {
+		return errors.New("Failed to set role: " + err.Error())
+	}
+	d.SetPartial("role")
+
+	ipAddresses := []interface{}{}
+	if app.IPAddressPerTask != nil {
+		ipAddress := make(map[string]interface{})
+		ipAddress["network_name"] = app.IPAddressPerTask.NetworkName
+		if app.IPAddressPerTask.Groups != nil {
+			ipAddress["groups"] = *app.IPAddressPerTask.Groups
+		}
+		if app.IPAddressPerTask.Labels != nil {
+			ipAddress["labels"] = *app.IPAddressPerTask.Labels
+		}
+		if app.IPAddressPerTask.Discovery != nil && app.IPAddressPerTask.Discovery.Ports != nil {
+			ports := make([]map[string]interface{}, len(*app.IPAddressPerTask.Discovery.Ports))
+			for idx, port := range *app.IPAddressPerTask.Discovery.Ports {
+				ports[idx] = map[string]interface{}{
+					"name":     port.Name,
+					"number":   port.Number,
+					"protocol": port.Protocol,
+				}
+			}
+			ipAddress["discovery_ports"] = ports
+		}
+		ipAddresses = []interface{}{ipAddress}
+	} else if hasIPAddressNetwork(d, app) {
+		// Marathon 1.5 and later translate the IP-per-task configuration into
+		// networks, and no longer report it. It is kept while the app still
+		// runs on the network it was translated into.
+		ipAddresses = d.Get("ip_address").([]interface{})
+	}
+
+	err = d.Set("ip_address", ipAddresses)
+	if err != nil {
+		return errors.New("Failed to set ip_address: " + err.Error())
+	}
+	d.SetPartial("ip_address")
+
 	err = d.Set("kill_selection", app.KillSelection)
 	if err != nil {
 		return errors.New("Failed to set kill_selection: " + err.Error())
@@ -1210,30 +1622,121 @@ func setSchemaFieldsForApp(app *marathon.Application, d *schema.ResourceData) er
 	return nil
 }
 
+/**
+ * readAppDefinitionJSON reads the app of a resource configured with a JSON
+ * definition
+ */
+func readAppDefinitionJSON(ctx context.Context, d *schema.ResourceData, config marathonConf) error {
+	raw, err := config.applicationJSON(d.Id())
+	if err != nil {
+		// Handle a deleted app
+		if apiErr, ok := err.(*marathon.APIError); ok && apiErr.ErrCode == marathon.ErrCodeNotFound {
+			d.SetId("")
+			return nil
+		}
+		return util.StepError(ctx, "reading app "+d.Id(), err)
+	}
+
+	var reported map[string]interface{}
+	if err := json.Unmarshal(raw, &reported); err != nil {
+		return fmt.Errorf("Unable to parse app %s: %s", d.Id(), err.Error())
+	}
+
+	definition, err := normalizeDefinitionJSON(reported, d.Get("definition_json").(string), marathonAppDefinitionDefaults)
+	if err != nil {
+		return fmt.Errorf("Unable to normalize app %s: %s", d.Id(), err.Error())
+	}
+
+	d.Set("definition_json", definition)
+	d.Set("app_id", reported["id"])
+	d.Set("version", reported["version"])
+
+	return nil
+}
+
+/**
+ * setSchemaFieldsForAppExtensions sets the app fields go-marathon does not model
+ */
+func setSchemaFieldsForAppExtensions(extensions marathonAppExtensions, d *schema.ResourceData) error {
+	tty := extensions.TTY != nil && *extensions.TTY
+	err := d.Set("tty", tty)
+	if err != nil {
+		return errors.New("Failed to set tty: " + err.Error())
+	}
+	d.SetPartial("tty")
+
+	if extensions.ResourceLimits != nil && len(*extensions.ResourceLimits) > 0 {
+		limits := make(map[string]interface{})
+		for _, key := range []string{"cpus", "mem"} {
+			switch value := (*extensions.ResourceLimits)[key].(type) {
+			case float64:
+				limits[key] = strconv.FormatFloat(value, 'f', -1, 64)
+			case string:
+				limits[key] = value
+			}
+		}
+
+		err := d.Set("resource_limits", []interface{}{limits})
+		if err != nil {
+			return errors.New("Failed to set resource_limits: " + err.Error())
+		}
+	} else {
+		d.Set("resource_limits", nil)
+	}
+	d.SetPartial("resource_limits")
+
+	return nil
+}
+
+/**
+ * hasIPAddressNetwork checks if the app runs on the container network its
+ * IP-per-task configuration was translated into
+ */
+func hasIPAddressNetwork(d *schema.ResourceData, app *marathon.Application) bool {
+	ipAddresses := d.Get("ip_address").([]interface{})
+	if len(ipAddresses) == 0 || app.Networks == nil || len(*app.Networks) != 1 {
+		return false
+	}
+
+	network := (*app.Networks)[0]
+	return network.Mode == marathon.ContainerNetworkMode && network.Name == d.Get("ip_address.0.network_name").(string)
+}
+
 func resourceDcosMarathonAppUpdate(d *schema.ResourceData, meta interface{}) error {
-	config, err := genMarathonConf(d, meta)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
 		return err
 	}
-	client := config.Client
 
-	c := make(chan deploymentEvent, 100)
-	ready := make(chan bool)
-	go readDeploymentEvents(&client, c, ready)
-	select {
-	case <-ready:
-	case <-time.After(60 * time.Second):
-		return errors.New("Timeout getting an EventListener")
+	definition, partial, err := appDefinitionFromResource(d)
+	if err != nil {
+		return err
 	}
+	previousVersion := d.Get("version").(string)
 
-	application := mapResourceToApplication(d)
+	// Make sure we are listening for the deployment events before deploying
+	config.Deployments.subscribe(ctx)
 
-	deploymentID, err := client.UpdateApplication(application, true)
+	deploymentID, err := config.updateApplication(d.Id(), definition, true, partial)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating app "+d.Id(), err)
 	}
 
-	err = waitOnSuccessfulDeployment(c, deploymentID.DeploymentID, config.DefaultDeploymentTimeout)
+	err = waitOnSuccessfulDeployment(ctx, config, deploymentID.DeploymentID, deploymentTarget{version: deploymentID.Version, apps: []string{d.Id()}})
+	if err != nil {
+		diagnostics := collectDeploymentDiagnostics(d, meta, d.Id(), false)
+		if d.Get("rollback_on_failure").(bool) {
+			// Keep the previous configuration in the state
+			d.Partial(true)
+			err = rollbackAppDeployment(d, meta, schema.TimeoutUpdate, deploymentID, previousVersion, err)
+		}
+		return withDeploymentDiagnostics(err, diagnostics)
+	}
+
+	err = waitForFrameworkPlan(ctx, d, meta)
 	if err != nil {
 		return err
 	}
@@ -1242,7 +1745,10 @@ func resourceDcosMarathonAppUpdate(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceDcosMarathonAppDelete(d *schema.ResourceData, meta interface{}) error {
-	config, err := genMarathonConf(d, meta)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
 		return err
 	}
@@ -1250,12 +1756,70 @@ func resourceDcosMarathonAppDelete(d *schema.ResourceData, meta interface{}) err
 
 	_, err = client.DeleteApplication(d.Id(), true)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting app "+d.Id(), err)
 	}
 
 	return nil
 }
 
+/**
+ * appDefinitionFromResource returns the app definition to send to Marathon,
+ * and whether it is merged into the current definition on updates. JSON
+ * definitions are sent as-is and replace the current definition.
+ */
+func appDefinitionFromResource(d *schema.ResourceData) (map[string]interface{}, bool, error) {
+	if v, ok := d.GetOk("definition_json"); ok {
+		definition, err := parseDefinitionJSON(v.(string), d.Get("app_id").(string))
+		return definition, false, err
+	}
+
+	definition, err := appDefinition(mapResourceToApplication(d), mapResourceToAppExtensions(d))
+	if err != nil {
+		return nil, true, fmt.Errorf("Unable to encode app definition: %s", err.Error())
+	}
+
+	// Marathon keeps fields missing from an update, so removed ones are reset
+	for key, field := range map[string]string{"residency": "residency", "task_kill_grace_period_seconds": "taskKillGracePeriodSeconds"} {
+		if _, ok := d.GetOk(key); !ok && d.HasChange(key) {
+			definition[field] = nil
+		}
+	}
+
+	return definition, true, nil
+}
+
+/**
+ * mapResourceToAppExtensions returns the app fields go-marathon does not model.
+ * Fields removed from the configuration are reset explicitly, since Marathon
+ * keeps the previous value of fields missing from an update.
+ */
+func mapResourceToAppExtensions(d *schema.ResourceData) marathonAppExtensions {
+	extensions := marathonAppExtensions{}
+
+	if v, ok := d.GetOk("tty"); ok || d.HasChange("tty") {
+		tty := v.(bool)
+		extensions.TTY = &tty
+	}
+
+	if _, ok := d.GetOk("resource_limits"); ok || d.HasChange("resource_limits") {
+		limits := make(map[string]interface{})
+		for _, key := range []string{"cpus", "mem"} {
+			value := d.Get("resource_limits.0." + key).(string)
+			if value == "" {
+				continue
+			}
+			if limit, err := strconv.ParseFloat(value, 64); err == nil {
+				limits[key] = limit
+			} else {
+				limits[key] = value
+			}
+		}
+		extensions.ResourceLimits = &limits
+	}
+
+	return extensions
+}
+
 func mapResourceToApplication(d *schema.ResourceData) *marathon.Application {
 	application := new(marathon.Application)
 
@@ -1753,6 +2317,107 @@ func mapResourceToApplication(d *schema.ResourceData) *marathon.Application {
 		}
 	}
 
+	if v, ok := d.GetOk("readiness_checks.#"); ok {
+		readinessChecks := make([]marathon.ReadinessCheck, v.(int))
+
+		for i := range readinessChecks {
+			mapStruct := d.Get("readiness_checks." + strconv.Itoa(i)).(map[string]interface{})
+			readinessCheck := new(marathon.ReadinessCheck)
+
+			readinessCheck.SetName(mapStruct["name"].(string))
+			readinessCheck.Protocol = mapStruct["protocol"].(string)
+			readinessCheck.Path = mapStruct["path"].(string)
+			readinessCheck.PortName = mapStruct["port_name"].(string)
+			readinessCheck.IntervalSeconds = mapStruct["interval_seconds"].(int)
+			readinessCheck.TimeoutSeconds = mapStruct["timeout_seconds"].(int)
+			readinessCheck.SetPreserveLastResponse(mapStruct["preserve_last_response"].(bool))
+
+			codesList := mapStruct["http_status_codes_for_ready"].([]interface{})
+			if len(codesList) > 0 {
+				codes := make([]int, len(codesList))
+				for index, value := range codesList {
+					codes[index] = value.(int)
+				}
+				readinessCheck.SetHTTPStatusCodesForReady(codes)
+			}
+
+			readinessChecks[i] = *readinessCheck
+		}
+
+		application.ReadinessChecks = &readinessChecks
+	} else {
+		readinessChecks := make([]marathon.ReadinessCheck, 0)
+		application.ReadinessChecks = &readinessChecks
+	}
+
+	if _, ok := d.GetOk("residency"); ok {
+		residency := new(marathon.Residency)
+		if v, ok := d.GetOk("residency.0.task_lost_behavior"); ok {
+			residency.SetTaskLostBehavior(marathon.TaskLostBehaviorType(v.(string)))
+		}
+		if v, ok := d.GetOk("residency.0.relaunch_escalation_timeout_seconds"); ok {
+			residency.RelaunchEscalationTimeoutSeconds = v.(int)
+		}
+		application.Residency = residency
+	}
+
+	if v, ok := d.GetOk("task_kill_grace_period_seconds"); ok {
+		v := v.(float64)
+		application.TaskKillGracePeriodSeconds = &v
+	}
+
+	if v, ok := d.GetOk("role"); ok {
+		v := v.(string)
+		application.Role = &v
+	}
+
+	if _, ok := d.GetOk("ip_address"); ok {
+		ipAddress := new(marathon.IPAddressPerTask)
+
+		if v, ok := d.GetOk("ip_address.0.network_name"); ok {
+			ipAddress.NetworkName = v.(string)
+		}
+
+		groupsList := d.Get("ip_address.0.groups").([]interface{})
+		if len(groupsList) > 0 {
+			groups := make([]string, len(groupsList))
+			for index, value := range groupsList {
+				groups[index] = value.(string)
+			}
+			ipAddress.Groups = &groups
+		}
+
+		labelsMap := d.Get("ip_address.0.labels").(map[string]interface{})
+		if len(labelsMap) > 0 {
+			labels := make(map[string]string, len(labelsMap))
+			for key, value := range labelsMap {
+				labels[key] = value.(string)
+			}
+			ipAddress.Labels = &labels
+		}
+
+		if v, ok := d.GetOk("ip_address.0.discovery_ports.#"); ok {
+			ports := make([]marathon.Port, v.(int))
+			for i := range ports {
+				portMap := d.Get(fmt.Sprintf("ip_address.0.discovery_ports.%d", i)).(map[string]interface{})
+				ports[i].Name = portMap["name"].(string)
+				ports[i].Number = portMap["number"].(int)
+				ports[i].Protocol = portMap["protocol"].(string)
+			}
+			ipAddress.Discovery = &marathon.Discovery{Ports: &ports}
+		}
+
+		application.IPAddressPerTask = ipAddress
+
+		// Marathon derives the networks from the IP-per-task configuration
+		application.Networks = nil
+	} else if o, _ := d.GetChange("ip_address"); len(o.([]interface{})) > 0 && !d.HasChange("networks") {
+		// Marathon keeps the networks derived from the removed IP-per-task
+		// configuration, unless they are replaced explicitly
+		application.Networks = nil
+		application.SetNetwork("", marathon.HostNetworkMode)
+	}
+
 	if v, ok := d.GetOk("kill_selection"); ok {
 		v := v.(string)
 		application.KillSelection = v
@@ -0,0 +1,655 @@
+package dcos
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test deploying and scaling an app against the test server */
+func TestDcosMarathonApp_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := func(instances int) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_marathon_app" "test" {
+  app_id    = "/test/app"
+  cmd       = "sleep 3600"
+  cpus      = 0.1
+  mem       = 32
+  instances = %d
+}
+`, instances)
+	}
+
+	checkInstances := func(expected int) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			app, ok := server.App("", "/test/app")
+			if !ok {
+				return fmt.Errorf("App was not created")
+			}
+			if instances := app["instances"]; instances != float64(expected) {
+				return fmt.Errorf("App has %v instances, expected %d", instances, expected)
+			}
+			if deployments := server.Deployments(""); len(deployments) != 0 {
+				return fmt.Errorf("Deployments %v are still in progress", deployments)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.App("", "/test/app"); ok {
+				return fmt.Errorf("App still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(1),
+				Check:  checkInstances(1),
+			},
+			{
+				Config: config(2),
+				Check:  checkInstances(2),
+			},
+		},
+	})
+}
+
+/** Test importing an app from a Marathon-on-Marathon instance */
+func TestDcosMarathonApp_import(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.AddMarathon("/service/marathon-user")
+
+	config := server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  marathon_service_url = "service/marathon-user"
+  app_id               = "/test/app"
+  cmd                  = "sleep 3600"
+  cpus                 = 0.1
+  mem                  = 32
+
+  container {
+    type = "DOCKER"
+
+    docker {
+      image = "nginx"
+    }
+
+    port_mappings {
+      container_port = 80
+      host_port      = 0
+      protocol       = "tcp"
+      service_port   = 10080
+    }
+  }
+
+  networks {
+    mode = "CONTAINER/BRIDGE"
+  }
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: func(*terraform.State) error {
+					if _, ok := server.App("/service/marathon-user", "/test/app"); !ok {
+						return fmt.Errorf("App was not created on marathon-user")
+					}
+					return nil
+				},
+			},
+			{
+				Config:            config,
+				ResourceName:      "dcos_marathon_app.test",
+				ImportState:       true,
+				ImportStateId:     "service/marathon-user:/test/app",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+/** Test waiting for the deployment plan of a framework */
+func TestDcosMarathonApp_framework(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.SetPlanStatus("test/framework", "deploy", "IN_PROGRESS")
+
+	config := func(appID, timeout string) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_marathon_app" "test" {
+  app_id = "%s"
+  cmd    = "sleep 3600"
+  cpus   = 0.1
+  mem    = 32
+
+  dcos_framework {
+    is_framework = true
+    plan_path    = "v1/plans/deploy"
+  }
+
+  timeouts {
+    create = "%s"
+  }
+}
+`, appID, timeout)
+	}
+
+	// The plan never completes within the timeout
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config:      config("/test/framework", "2s"),
+				ExpectError: regexp.MustCompile("Timed out while waiting for the plan of framework test/framework"),
+			},
+		},
+	})
+
+	// The plan completes while waiting
+	var completed bool
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					server.SetPlanStatus("other/framework", "deploy", "IN_PROGRESS")
+					time.AfterFunc(time.Second, func() {
+						completed = true
+						server.SetPlanStatus("other/framework", "deploy", testserver.PlanComplete)
+					})
+				},
+				Config: config("/other/framework", "1m"),
+				Check: func(*terraform.State) error {
+					if !completed {
+						return fmt.Errorf("Did not wait for the deployment plan")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/** Test that apps share a single event stream subscription */
+func TestDcosMarathonApp_sharedEventStream(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "first" {
+  app_id = "/test/first"
+  cmd    = "sleep 3600"
+}
+
+resource "dcos_marathon_app" "second" {
+  app_id = "/test/second"
+  cmd    = "sleep 3600"
+}
+`,
+				Check: func(*terraform.State) error {
+					if subscribers := server.EventSubscribers(""); subscribers != 1 {
+						return fmt.Errorf("Expected 1 event stream subscriber, found %d", subscribers)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/** Test waiting for deployments when the event stream is not available */
+func TestDcosMarathonApp_pollDeployments(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.DisableEventStream = true
+
+	defer func(interval time.Duration) { deploymentPollInterval = interval }(deploymentPollInterval)
+	deploymentPollInterval = 100 * time.Millisecond
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id    = "/test/app"
+  cmd       = "sleep 3600"
+  instances = 2
+}
+`,
+				Check: func(*terraform.State) error {
+					if deployments := server.Deployments(""); len(deployments) != 0 {
+						return fmt.Errorf("Deployments %v are still in progress", deployments)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/**
+ * Test that a failed deployment is not mistaken for a success when its events
+ * are not received
+ */
+func TestDcosMarathonApp_pollFailedDeployment(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.DisableEventStream = true
+	server.FailDeployments("", "/test/app", "Command exited with status 1")
+
+	defer func(interval time.Duration) { deploymentPollInterval = interval }(deploymentPollInterval)
+	deploymentPollInterval = 100 * time.Millisecond
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id    = "/test/app"
+  cmd       = "exit 1"
+  instances = 2
+}
+`,
+				ExpectError: regexp.MustCompile("Outcome of deployment .* is unknown, app /test/app is at version .* with 0 of 2 instances running"),
+			},
+		},
+	})
+}
+
+/**
+ * Test that a deployment which finished before it was seen running is only
+ * reported as successful if its apps are at the deployed version
+ */
+func TestDcosMarathonApp_verifyUnseenDeployment(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.CreateApp("", map[string]interface{}{"id": "/test/app", "cmd": "sleep 3600", "instances": 1})
+
+	for len(server.Deployments("")) > 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	app, _ := server.App("", "/test/app")
+
+	meta, err := providerConfigure(schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"dcos_url": server.URL,
+		"user":     testserver.DefaultUser,
+		"password": testserver.DefaultPassword,
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	ctx := context.Background()
+	d := schema.TestResourceDataRaw(t, resourceDcosMarathonApp().Schema, map[string]interface{}{"app_id": "/test/app"})
+	config, err := genMarathonConf(ctx, d, meta)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	err = config.Deployments.wait(ctx, config, "unseen", deploymentTarget{version: app["version"].(string), apps: []string{"/test/app"}})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err.Error())
+	}
+
+	err = config.Deployments.wait(ctx, config, "unseen", deploymentTarget{version: "2000-01-01T00:00:00.000Z", apps: []string{"/test/app"}})
+	if err == nil || !strings.Contains(err.Error(), "app /test/app is at version") {
+		t.Errorf("Expected the version of the app to be reported, got %v", err)
+	}
+
+	err = config.Deployments.wait(ctx, config, "unseen", deploymentTarget{})
+	if err == nil || !strings.Contains(err.Error(), "finished before it was seen running") {
+		t.Errorf("Expected an unknown outcome, got %v", err)
+	}
+}
+
+/** Test rolling back an app whose update failed */
+func TestDcosMarathonApp_rollbackFailedUpdate(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := func(cmd string) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_marathon_app" "test" {
+  app_id              = "/test/app"
+  cmd                 = "%s"
+  rollback_on_failure = true
+}
+`, cmd)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: config("sleep 3600"),
+			},
+			{
+				PreConfig: func() {
+					server.FailDeployments("", "/test/app", "Command exited with status 1")
+				},
+				Config:      config("exit 1"),
+				ExpectError: regexp.MustCompile("deployment_failed .*Rolled back app /test/app to version"),
+			},
+			{
+				PreConfig: func() {
+					server.FailDeployments("", "/test/app", "")
+					app, _ := server.App("", "/test/app")
+					if cmd := app["cmd"]; cmd != "sleep 3600" {
+						t.Errorf("App was not rolled back, its command is %v", cmd)
+					}
+				},
+				Config: config("sleep 3600"),
+			},
+		},
+	})
+}
+
+/** Test cancelling the deployment of an app that timed out */
+func TestDcosMarathonApp_rollbackTimeout(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.HoldDeployments("", "/test/app", true)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.App("", "/test/app"); ok {
+				return fmt.Errorf("App was not removed by the rollback")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id              = "/test/app"
+  cmd                 = "sleep 3600"
+  rollback_on_failure = true
+
+  timeouts {
+    create = "2s"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("Timed out while waiting for deployment .*Cancelled the deployment and rolled back app /test/app"),
+			},
+		},
+	})
+}
+
+/** Test that failed deployments report why the app could not be launched */
+func TestDcosMarathonApp_deploymentDiagnostics(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.HoldDeployments("", "/test/stalled", true)
+	server.DeclineOffers("", "/test/stalled", map[string]int{
+		"InsufficientCpus":      3,
+		"UnfulfilledConstraint": 2,
+	})
+	server.FailDeployments("", "/test/failing", "Command exited with status 1")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id = "/test/stalled"
+  cmd    = "sleep 3600"
+  cpus   = 64
+
+  timeouts {
+    create = "2s"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Declined 5 of 5 offers: constraints not matched \(2 of 5 offers\), insufficient cpus \(3 of 5 offers\)`),
+			},
+		},
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id = "/test/failing"
+  cmd    = "exit 1"
+}
+`,
+				ExpectError: regexp.MustCompile("Last task failure: Command exited with status 1"),
+			},
+		},
+	})
+}
+
+/** Test reading back readiness checks, residency and the other app fields */
+func TestDcosMarathonApp_readinessAndLimits(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id                         = "/test/app"
+  cmd                            = "python3 -m http.server $PORT0"
+  task_kill_grace_period_seconds = 30
+  tty                            = true
+
+  port_definitions {
+    name = "http"
+  }
+
+  readiness_checks {
+    port_name        = "http"
+    path             = "/ready"
+    interval_seconds = 5
+  }
+
+  residency {
+    task_lost_behavior                  = "WAIT_FOREVER"
+    relaunch_escalation_timeout_seconds = 600
+  }
+
+  resource_limits {
+    cpus = "unlimited"
+    mem  = "512.0"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "readiness_checks.0.name", "readinessCheck"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "readiness_checks.0.path", "/ready"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "readiness_checks.0.http_status_codes_for_ready.0", "200"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "residency.0.task_lost_behavior", "WAIT_FOREVER"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "role", "*"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "resource_limits.0.cpus", "unlimited"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "resource_limits.0.mem", "512"),
+					func(*terraform.State) error {
+						app, _ := server.App("", "/test/app")
+						if app["tty"] != true {
+							return fmt.Errorf("Expected tty to be enabled, got %v", app["tty"])
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id = "/test/app"
+  cmd    = "python3 -m http.server $PORT0"
+
+  port_definitions {
+    name = "http"
+  }
+}
+`,
+				Check: func(*terraform.State) error {
+					app, _ := server.App("", "/test/app")
+					if app["tty"] != false {
+						return fmt.Errorf("Expected tty to be disabled, got %v", app["tty"])
+					}
+					if limits, _ := app["resourceLimits"].(map[string]interface{}); len(limits) != 0 {
+						return fmt.Errorf("Expected resource limits to be removed, got %v", limits)
+					}
+					if checks, _ := app["readinessChecks"].([]interface{}); len(checks) != 0 {
+						return fmt.Errorf("Expected readiness checks to be removed, got %v", checks)
+					}
+					if app["residency"] != nil || app["taskKillGracePeriodSeconds"] != nil {
+						return fmt.Errorf("Expected residency and the task kill grace period to be reset, got %v and %v", app["residency"], app["taskKillGracePeriodSeconds"])
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/** Test removing the IP-per-task configuration Marathon translated into networks */
+func TestDcosMarathonApp_removeIPAddress(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id = "/test/app"
+  cmd    = "sleep 3600"
+
+  ip_address {
+    network_name = "dcos"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "ip_address.0.network_name", "dcos"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "networks.0.mode", "CONTAINER"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id = "/test/app"
+  cmd    = "sleep 3600"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "ip_address.#", "0"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "networks.0.mode", "HOST"),
+					func(*terraform.State) error {
+						app, _ := server.App("", "/test/app")
+						networks, _ := app["networks"].([]interface{})
+						if len(networks) != 1 || networks[0].(map[string]interface{})["mode"] != "host" {
+							return fmt.Errorf("Expected the app to run on the host network, got %v", networks)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+/** Test deploying an app from its JSON definition */
+func TestDcosMarathonApp_definitionJSON(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id = "/test/app"
+
+  definition_json = <<EOF
+{
+  "cmd": "sleep 3600",
+  "mem": 32,
+  "labels": {"team": "infra"},
+  "portDefinitions": [{"port": 0, "name": "http"}]
+}
+EOF
+}
+`,
+				Check: resource.TestCheckResourceAttr("dcos_marathon_app.test", "definition_json",
+					`{"cmd":"sleep 3600","labels":{"team":"infra"},"mem":32,"portDefinitions":[{"name":"http","port":0}]}`),
+			},
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_app" "test" {
+  app_id = "/test/app"
+
+  definition_json = jsonencode({
+    id  = "/test/app"
+    cmd = "sleep 7200"
+    mem = 32
+  })
+}
+`,
+				Check: func(*terraform.State) error {
+					app, _ := server.App("", "/test/app")
+					if app["cmd"] != "sleep 7200" {
+						return fmt.Errorf("App was not updated, its command is %v", app["cmd"])
+					}
+					if labels, _ := app["labels"].(map[string]interface{}); len(labels) != 0 {
+						return fmt.Errorf("Expected the labels to be removed, got %v", labels)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/** Test that JSON definitions cannot be mixed with the structured fields */
+func TestDcosMarathonApp_definitionJSONConflicts(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "dcos_marathon_app" "test" {
+  app_id          = "/test/app"
+  cmd             = "sleep 3600"
+  definition_json = "{}"
+}
+`,
+				ExpectError: regexp.MustCompile(`"definition_json": conflicts with cmd`),
+			},
+		},
+	})
+}
@@ -0,0 +1,247 @@
+package dcos
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	marathon "github.com/gambol99/go-marathon"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
+)
+
+func resourceDcosMarathonGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDcosMarathonGroupCreate,
+		Read:   resourceDcosMarathonGroupRead,
+		Update: resourceDcosMarathonGroupUpdate,
+		Delete: resourceDcosMarathonGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceDcosMarathonGroupImport,
+		},
+
+		SchemaVersion: 1,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"marathon_service_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "service/marathon",
+				ForceNew:    true,
+				Description: "The service URL of the Marathon instance managing the group, to support Marathon-on-Marathon",
+			},
+			"group_id": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressEquivalentMarathonPath,
+				Description:      "The ID of the group",
+			},
+			"enforce_role": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the apps and pods in the group are forced to use the role named after the group, so they are subject to its quota. Only top-level groups can enforce their role.",
+			},
+			"dependencies": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the apps and groups the group depends on",
+			},
+			"prevent_recursive_delete": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Refuse to delete the group while it contains apps, pods or groups",
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+/**
+ * suppressEquivalentMarathonPath ignores a missing leading slash in app, pod
+ * and group IDs
+ */
+func suppressEquivalentMarathonPath(k, old, new string, d *schema.ResourceData) bool {
+	return marathonPath(old) == marathonPath(new)
+}
+
+func mapResourceToMarathonGroup(d *schema.ResourceData) *marathonGroup {
+	enforceRole := d.Get("enforce_role").(bool)
+	group := &marathonGroup{
+		ID:           marathonPath(d.Get("group_id").(string)),
+		EnforceRole:  &enforceRole,
+		Dependencies: make([]string, 0),
+	}
+
+	for _, dependency := range d.Get("dependencies").(*schema.Set).List() {
+		group.Dependencies = append(group.Dependencies, dependency.(string))
+	}
+
+	return group
+}
+
+func resourceDcosMarathonGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
+	if err != nil {
+		return err
+	}
+
+	group := mapResourceToMarathonGroup(d)
+
+	// Make sure we are listening for the deployment events before deploying
+	config.Deployments.subscribe(ctx)
+
+	log.Printf("[TRACE] Creating Marathon group %+v", group)
+
+	deployment, err := config.createGroup(group)
+	if err != nil {
+		return util.StepError(ctx, "creating group "+group.ID, err)
+	}
+	d.SetId(group.ID)
+
+	err = waitOnSuccessfulDeployment(ctx, config, deployment.DeploymentID, deploymentTarget{version: deployment.Version, groups: []string{group.ID}})
+	if err != nil {
+		return util.StepError(ctx, "waiting for the deployment of group "+group.ID, err)
+	}
+
+	return resourceDcosMarathonGroupRead(d, meta)
+}
+
+func resourceDcosMarathonGroupRead(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
+	if err != nil {
+		return err
+	}
+
+	group, err := config.group(d.Id())
+	if err != nil {
+		// Handle a deleted group
+		if apiErr, ok := err.(*marathon.APIError); ok && apiErr.ErrCode == marathon.ErrCodeNotFound {
+			d.SetId("")
+			return nil
+		}
+		return util.StepError(ctx, "reading group "+d.Id(), err)
+	}
+
+	log.Printf("[TRACE] Marathon group: %+v", group)
+
+	// Keep the group ID as configured, unless importing
+	if marathonPath(d.Get("group_id").(string)) != group.ID {
+		d.Set("group_id", group.ID)
+	}
+	d.Set("enforce_role", group.EnforceRole != nil && *group.EnforceRole)
+	d.Set("version", group.Version)
+	if err := d.Set("dependencies", group.Dependencies); err != nil {
+		return fmt.Errorf("Unable to set dependencies: %s", err.Error())
+	}
+
+	return nil
+}
+
+func resourceDcosMarathonGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
+
+	if !d.HasChange("enforce_role") && !d.HasChange("dependencies") {
+		return resourceDcosMarathonGroupRead(d, meta)
+	}
+
+	config, err := genMarathonConf(ctx, d, meta)
+	if err != nil {
+		return err
+	}
+
+	group := mapResourceToMarathonGroup(d)
+
+	// Make sure we are listening for the deployment events before deploying
+	config.Deployments.subscribe(ctx)
+
+	deployment, err := config.updateGroup(d.Id(), group, true)
+	if err != nil {
+		return util.StepError(ctx, "updating group "+d.Id(), err)
+	}
+
+	err = waitOnSuccessfulDeployment(ctx, config, deployment.DeploymentID, deploymentTarget{version: deployment.Version, groups: []string{d.Id()}})
+	if err != nil {
+		return util.StepError(ctx, "waiting for the deployment of group "+d.Id(), err)
+	}
+
+	return resourceDcosMarathonGroupRead(d, meta)
+}
+
+func resourceDcosMarathonGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
+
+	config, err := genMarathonConf(ctx, d, meta)
+	if err != nil {
+		return err
+	}
+
+	if d.Get("prevent_recursive_delete").(bool) {
+		group, err := config.group(d.Id())
+		if err != nil {
+			if apiErr, ok := err.(*marathon.APIError); ok && apiErr.ErrCode == marathon.ErrCodeNotFound {
+				d.SetId("")
+				return nil
+			}
+			return util.StepError(ctx, "reading group "+d.Id(), err)
+		}
+
+		if len(group.Apps) > 0 || len(group.Pods) > 0 || len(group.Groups) > 0 {
+			return fmt.Errorf("Unable to delete group %s: it still contains %d app(s), %d pod(s) and %d group(s), and prevent_recursive_delete is set", d.Id(), len(group.Apps), len(group.Pods), len(group.Groups))
+		}
+	}
+
+	// Make sure we are listening for the deployment events before deploying
+	config.Deployments.subscribe(ctx)
+
+	deployment, err := config.Client.DeleteGroup(d.Id(), true)
+	if err != nil {
+		if apiErr, ok := err.(*marathon.APIError); ok && apiErr.ErrCode == marathon.ErrCodeNotFound {
+			d.SetId("")
+			return nil
+		}
+		return util.StepError(ctx, "deleting group "+d.Id(), err)
+	}
+
+	err = waitOnSuccessfulDeployment(ctx, config, deployment.DeploymentID, deploymentTarget{groups: []string{d.Id()}})
+	if err != nil {
+		return util.StepError(ctx, "waiting for group "+d.Id()+" to be removed", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceDcosMarathonGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serviceURL, groupID, err := parseMarathonImportID(d.Id(), "group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("marathon_service_url", serviceURL)
+	d.Set("prevent_recursive_delete", false)
+	d.SetId(groupID)
+
+	log.Printf("[TRACE] Importing group %s from %s", d.Id(), serviceURL)
+
+	return []*schema.ResourceData{d}, nil
+}
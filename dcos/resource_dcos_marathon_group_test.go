@@ -0,0 +1,111 @@
+package dcos
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test creating, updating and importing a group enforcing its role */
+func TestDcosMarathonGroup_enforceRole(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := server.ProviderConfig() + `
+resource "dcos_marathon_group" "test" {
+  group_id     = "/team"
+  enforce_role = true
+}
+
+resource "dcos_marathon_app" "test" {
+  app_id = "${dcos_marathon_group.test.group_id}/app"
+  cmd    = "sleep 3600"
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.MarathonGroup("", "/team"); ok {
+				return fmt.Errorf("Expected group /team to be removed")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_marathon_group.test", "id", "/team"),
+					resource.TestCheckResourceAttr("dcos_marathon_group.test", "enforce_role", "true"),
+					resource.TestCheckResourceAttrSet("dcos_marathon_group.test", "version"),
+					resource.TestCheckResourceAttr("dcos_marathon_app.test", "role", "team"),
+				),
+			},
+			{
+				Config: strings.Replace(config, "enforce_role = true", "dependencies = [\"/database\"]", 1),
+				Check: func(*terraform.State) error {
+					group, _ := server.MarathonGroup("", "/team")
+					if group["enforceRole"] != false {
+						return fmt.Errorf("Expected enforceRole to be disabled, got %v", group["enforceRole"])
+					}
+					if fmt.Sprint(group["dependencies"]) != "[/database]" {
+						return fmt.Errorf("Expected dependencies [/database], got %v", group["dependencies"])
+					}
+					return nil
+				},
+			},
+			{
+				Config:            strings.Replace(config, "enforce_role = true", "dependencies = [\"/database\"]", 1),
+				ResourceName:      "dcos_marathon_group.test",
+				ImportState:       true,
+				ImportStateId:     "/team",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+/** Test refusing to delete a group still containing apps, unless allowed */
+func TestDcosMarathonGroup_preventRecursiveDelete(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.App("", "/team/app"); ok {
+				return fmt.Errorf("Expected app /team/app to be removed with its group")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_group" "test" {
+  group_id                 = "/team"
+  prevent_recursive_delete = true
+}
+`,
+			},
+			{
+				PreConfig: func() {
+					server.CreateApp("", map[string]interface{}{"id": "/team/app", "cmd": "sleep 3600"})
+				},
+				Config:      server.ProviderConfig(),
+				ExpectError: regexp.MustCompile("still contains 1 app"),
+			},
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_group" "test" {
+  group_id = "/team"
+}
+`,
+			},
+		},
+	})
+}
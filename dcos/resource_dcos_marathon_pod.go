@@ -1,6 +1,8 @@
 package dcos
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -11,16 +13,17 @@ import (
 	"github.com/hashicorp/terraform/helper/validation"
 
 	marathon "github.com/gambol99/go-marathon"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosMarathonPod() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceDcosMarathonPodCreate,
 		Read:   resourceDcosMarathonPodRead,
 		Update: resourceDcosMarathonPodUpdate,
 		Delete: resourceDcosMarathonPodDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceDcosMarathonPodImport,
 		},
 
 		SchemaVersion: 1,
@@ -622,8 +625,26 @@ func resourceDcosMarathonPod() *schema.Resource {
 					},
 				},
 			},
+			"replace_triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values which replace all instances of the pod when changed, without changing the pod definition",
+			},
+			"replace_batch_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "How many instances are replaced at once when the replace_triggers change",
+			},
 		},
 	}
+
+	r.Schema["definition_json"] = definitionJSONSchema(r.Schema,
+		"name", "marathon_service_url", "replace_triggers", "replace_batch_size")
+
+	return r
 }
 
 func schemaToMarathonPod(d *schema.ResourceData) (*marathon.Pod, error) {
@@ -1148,21 +1169,32 @@ func schemaToMarathonPod(d *schema.ResourceData) (*marathon.Pod, error) {
 }
 
 func resourceDcosMarathonPodCreate(d *schema.ResourceData, meta interface{}) error {
-	// client := meta.(*dcos.APIClient)
-	// ctx := context.TODO()
-	mconf, err := genMarathonConf(d, meta)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
+
+	mconf, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
 		return err
 	}
 
-	pod, err := schemaToMarathonPod(d)
+	name := d.Get("name").(string)
+	definition, err := podDefinitionFromResource(d)
 	if err != nil {
 		return err
 	}
 
-	log.Printf("[TRACE] Marathon.POD Creating POD %+v", pod)
+	log.Printf("[TRACE] Marathon.POD Creating POD %+v", definition)
+
+	// Make sure we are listening for the deployment events before deploying
+	mconf.Deployments.subscribe(ctx)
 
-	_, err = mconf.Client.CreatePod(pod)
+	deploymentID, err := mconf.createPod(definition)
+	if err != nil {
+		return util.StepError(ctx, "creating pod "+name, err)
+	}
+	d.SetId(name)
+
+	err = waitOnPodDeployment(ctx, d, meta, mconf, deploymentID, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
 		return err
 	}
@@ -1170,17 +1202,189 @@ func resourceDcosMarathonPodCreate(d *schema.ResourceData, meta interface{}) err
 	return resourceDcosMarathonPodRead(d, meta)
 }
 
+/**
+ * podDefinitionFromResource returns the pod definition to send to Marathon
+ */
+func podDefinitionFromResource(d *schema.ResourceData) (map[string]interface{}, error) {
+	if v, ok := d.GetOk("definition_json"); ok {
+		return parseDefinitionJSON(v.(string), d.Get("name").(string))
+	}
+
+	pod, err := schemaToMarathonPod(d)
+	if err != nil {
+		return nil, err
+	}
+
+	definition, err := podDefinition(pod)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to encode pod definition: %s", err.Error())
+	}
+	return definition, nil
+}
+
+/**
+ * waitOnPodDeployment waits until the deployment of the pod is completed, and
+ * the pod reports the desired number of stable instances. Failures are
+ * described by the status of the pod instances and their containers.
+ */
+func waitOnPodDeployment(ctx context.Context, d *schema.ResourceData, meta interface{}, mconf marathonConf, deploymentID string, timeout time.Duration) error {
+	name := d.Id()
+
+	if deploymentID != "" {
+		err := waitOnSuccessfulDeployment(ctx, mconf, deploymentID, deploymentTarget{pods: []string{name}})
+		if err != nil {
+			log.Printf("[ERROR] waiting for pod deployment %s: %s", deploymentID, err.Error())
+			return withDeploymentDiagnostics(util.StepError(ctx, "waiting for the deployment of pod "+name, err), collectDeploymentDiagnostics(d, meta, name, true))
+		}
+	}
+
+	return waitOnStablePod(ctx, d, meta, mconf, timeout, nil)
+}
+
+/**
+ * waitOnStablePod waits until the pod reports the desired number of stable
+ * instances, not counting the given instances being replaced
+ */
+func waitOnStablePod(ctx context.Context, d *schema.ResourceData, meta interface{}, mconf marathonConf, timeout time.Duration, replaced []string) error {
+	name := d.Id()
+
+	isReplaced := make(map[string]bool)
+	for _, id := range replaced {
+		isReplaced[id] = true
+	}
+
+	err := util.RetryContext(ctx, timeout, func() *resource.RetryError {
+		status, err := mconf.Client.PodStatus(name)
+		if err != nil {
+			// Leader elections and restarts of Marathon are ridden out
+			if apiErr, ok := err.(*marathon.APIError); (ok && apiErr.ErrCode == marathon.ErrCodeNotFound) || ctx.Err() != nil {
+				return resource.NonRetryableError(err)
+			}
+			log.Printf("[WARN] Unable to read the status of pod %s: %s", name, err.Error())
+			return resource.RetryableError(err)
+		}
+
+		desired := 1
+		if status.Spec != nil && status.Spec.Scaling != nil {
+			desired = status.Spec.Scaling.Instances
+		}
+
+		stable := 0
+		for _, instance := range status.Instances {
+			if instance.Status == marathon.PodInstanceStateStable && !isReplaced[instance.ID] {
+				stable++
+			}
+		}
+
+		if status.Status != marathon.PodStateStable || stable < desired {
+			return resource.RetryableError(fmt.Errorf("Pod %s is %s with %d of %d instances stable", name, status.Status, stable, desired))
+		}
+		return nil
+	})
+	if err != nil {
+		return withDeploymentDiagnostics(util.StepError(ctx, "waiting for pod "+name+" to become stable", err), collectDeploymentDiagnostics(d, meta, name, true))
+	}
+
+	return nil
+}
+
+/**
+ * podInstanceIDs returns the IDs of the current instances of the pod
+ */
+func podInstanceIDs(ctx context.Context, mconf marathonConf, name string) ([]string, error) {
+	status, err := mconf.Client.PodStatus(name)
+	if err != nil {
+		return nil, util.StepError(ctx, "reading the status of pod "+name, err)
+	}
+
+	var instances []string
+	for _, instance := range status.Instances {
+		instances = append(instances, instance.ID)
+	}
+	return instances, nil
+}
+
+/**
+ * replacePodInstances replaces the given instances of the pod, in batches of
+ * replace_batch_size. Each batch waits for its replacements to become stable.
+ * Instances which are gone already are skipped.
+ */
+func replacePodInstances(ctx context.Context, d *schema.ResourceData, meta interface{}, mconf marathonConf, timeout time.Duration, replaced []string) error {
+	name := d.Id()
+	batchSize := d.Get("replace_batch_size").(int)
+
+	current, err := podInstanceIDs(ctx, mconf, name)
+	if err != nil {
+		return err
+	}
+
+	isCurrent := make(map[string]bool)
+	for _, id := range current {
+		isCurrent[id] = true
+	}
+
+	var instances []string
+	for _, id := range replaced {
+		if isCurrent[id] {
+			instances = append(instances, id)
+		}
+	}
+
+	for start := 0; start < len(instances); start += batchSize {
+		end := start + batchSize
+		if end > len(instances) {
+			end = len(instances)
+		}
+		batch := instances[start:end]
+
+		log.Printf("[TRACE] Replacing instances %v of pod %s", batch, name)
+
+		if err := mconf.deletePodInstances(name, batch); err != nil {
+			return util.StepError(ctx, "replacing instances of pod "+name, err)
+		}
+
+		if err := waitOnStablePod(ctx, d, meta, mconf, timeout, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resourceDcosMarathonPodImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serviceURL, podID, err := parseMarathonImportID(d.Id(), "pod_id")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("marathon_service_url", serviceURL)
+	d.Set("name", podID)
+	d.Set("replace_batch_size", 1)
+	d.SetId(podID)
+
+	log.Printf("[TRACE] Importing pod %s from %s", d.Id(), serviceURL)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceDcosMarathonPodRead(d *schema.ResourceData, meta interface{}) error {
-	mconf, err := genMarathonConf(d, meta)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
+
+	mconf, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
 		return err
 	}
 
 	name := d.Get("name").(string)
 
+	if _, ok := d.GetOk("definition_json"); ok {
+		return readPodDefinitionJSON(ctx, d, mconf)
+	}
+
 	pod, err := mconf.Client.Pod(name)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading pod "+name, err)
 	}
 
 	if pod == nil {
@@ -1493,31 +1697,133 @@ func resourceDcosMarathonPodRead(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func resourceDcosMarathonPodUpdate(d *schema.ResourceData, meta interface{}) error {
-	// client := meta.(*dcos.APIClient)
-	// ctx := context.TODO()
-	mconf, err := genMarathonConf(d, meta)
+/**
+ * readPodDefinitionJSON reads the pod of a resource configured with a JSON
+ * definition
+ */
+func readPodDefinitionJSON(ctx context.Context, d *schema.ResourceData, mconf marathonConf) error {
+	name := d.Get("name").(string)
+
+	raw, err := mconf.podJSON(name)
 	if err != nil {
-		return err
+		// Handle a deleted pod
+		if apiErr, ok := err.(*marathon.APIError); ok && apiErr.ErrCode == marathon.ErrCodeNotFound {
+			d.SetId("")
+			return nil
+		}
+		return util.StepError(ctx, "reading pod "+name, err)
 	}
 
-	pod, err := schemaToMarathonPod(d)
+	var reported map[string]interface{}
+	if err := json.Unmarshal(raw, &reported); err != nil {
+		return fmt.Errorf("Unable to parse pod %s: %s", name, err.Error())
+	}
+
+	definition, err := normalizeDefinitionJSON(reported, d.Get("definition_json").(string), marathonPodDefinitionDefaults)
 	if err != nil {
-		return err
+		return fmt.Errorf("Unable to normalize pod %s: %s", name, err.Error())
 	}
 
-	_, err = mconf.Client.UpdatePod(pod, true)
+	d.SetId(name)
+	d.Set("definition_json", definition)
+
+	return nil
+}
+
+/**
+ * The fields making up the pod definition. The name and the service URL force
+ * a new pod, while replace_triggers and replace_batch_size only control the
+ * replacement of instances.
+ */
+var podDefinitionFields = []string{
+	"container",
+	"definition_json",
+	"executor_resources",
+	"labels",
+	"network",
+	"scaling",
+	"scheduling",
+	"secrets",
+	"user",
+	"volume",
+}
+
+/**
+ * hasPodFieldChange works like HasChange, but compares sets by the hashes of
+ * their elements, which are not affected by zero values of nested fields
+ */
+func hasPodFieldChange(d *schema.ResourceData, key string) bool {
+	o, n := d.GetChange(key)
+	if oldSet, ok := o.(*schema.Set); ok {
+		newSet := n.(*schema.Set)
+		return oldSet.Difference(newSet).Len() > 0 || newSet.Difference(oldSet).Len() > 0
+	}
+	return d.HasChange(key)
+}
+
+func resourceDcosMarathonPodUpdate(d *schema.ResourceData, meta interface{}) error {
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
+
+	mconf, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
 		return err
 	}
 
+	definitionChanged := false
+	for _, k := range podDefinitionFields {
+		if hasPodFieldChange(d, k) {
+			definitionChanged = true
+		}
+	}
+
+	// Deploying the definition may keep some instances, eg. when only scaling
+	// changes, so the instances to replace are collected before
+	var replaced []string
+	if d.HasChange("replace_triggers") {
+		replaced, err = podInstanceIDs(ctx, mconf, d.Id())
+		if err != nil {
+			return err
+		}
+	}
+
+	if definitionChanged {
+		definition, err := podDefinitionFromResource(d)
+		if err != nil {
+			return err
+		}
+
+		// Make sure we are listening for the deployment events before deploying
+		mconf.Deployments.subscribe(ctx)
+
+		deploymentID, err := mconf.updatePod(d.Id(), definition, true)
+		if err != nil {
+			return util.StepError(ctx, "updating pod "+d.Id(), err)
+		}
+
+		err = waitOnPodDeployment(ctx, d, meta, mconf, deploymentID, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			d.Partial(true)
+			return err
+		}
+	}
+
+	if len(replaced) > 0 {
+		err = replacePodInstances(ctx, d, meta, mconf, d.Timeout(schema.TimeoutUpdate), replaced)
+		if err != nil {
+			d.Partial(true)
+			return err
+		}
+	}
+
 	return resourceDcosMarathonPodRead(d, meta)
 }
 
 func resourceDcosMarathonPodDelete(d *schema.ResourceData, meta interface{}) error {
-	// client := meta.(*dcos.APIClient)
-	// ctx := context.TODO()
-	mconf, err := genMarathonConf(d, meta)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
+
+	mconf, err := genMarathonConf(ctx, d, meta)
 	if err != nil {
 		return err
 	}
@@ -1526,10 +1832,10 @@ func resourceDcosMarathonPodDelete(d *schema.ResourceData, meta interface{}) err
 
 	dpl, err := mconf.Client.DeletePod(name, true)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting pod "+name, err)
 	}
 
-	err = resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+	err = util.RetryContext(ctx, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		ok, err := mconf.Client.HasDeployment(dpl.DeploymentID)
 		if ok {
 			return resource.RetryableError(fmt.Errorf("Delete still in progress"))
@@ -1543,7 +1849,7 @@ func resourceDcosMarathonPodDelete(d *schema.ResourceData, meta interface{}) err
 	})
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "waiting for pod "+name+" to be removed", err)
 	}
 
 	d.SetId("")
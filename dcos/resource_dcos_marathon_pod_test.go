@@ -0,0 +1,333 @@
+package dcos
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test creating and deleting a pod against the test server */
+func TestDcosMarathonPod_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.Pod("", "/test/pod"); ok {
+				return fmt.Errorf("Pod still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_pod" "test" {
+  name = "/test/pod"
+
+  container {
+    name = "sleep"
+    exec {
+      command_shell = "sleep 3600"
+    }
+    resources {
+      cpus = 0.1
+      mem  = 32
+    }
+  }
+}
+`,
+				Check: func(*terraform.State) error {
+					if _, ok := server.Pod("", "/test/pod"); !ok {
+						return fmt.Errorf("Pod was not created")
+					}
+					if deployments := server.Deployments(""); len(deployments) > 0 {
+						return fmt.Errorf("Expected the deployment of the pod to be completed, got %v", deployments)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/** Test importing a pod from a Marathon-on-Marathon instance */
+func TestDcosMarathonPod_import(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.AddMarathon("/service/marathon-user")
+
+	config := server.ProviderConfig() + `
+resource "dcos_marathon_pod" "test" {
+  marathon_service_url = "service/marathon-user"
+  name                 = "/test/pod"
+
+  container {
+    name = "sleep"
+    exec {
+      command_shell = "sleep 3600"
+    }
+    resources {
+      cpus = 0.1
+      mem  = 32
+    }
+  }
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config:            config,
+				ResourceName:      "dcos_marathon_pod.test",
+				ImportState:       true,
+				ImportStateId:     "service/marathon-user:/test/pod",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+/** Test deploying a pod from its JSON definition, and recreating it once removed */
+func TestDcosMarathonPod_definitionJSON(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := server.ProviderConfig() + `
+resource "dcos_marathon_pod" "test" {
+  name = "/test/pod"
+
+  definition_json = jsonencode({
+    scaling = { kind = "fixed", instances = 1 }
+    containers = [{
+      name      = "sleep"
+      exec      = { command = { shell = "sleep 3600" } }
+      resources = { cpus = 0.1, mem = 32 }
+    }]
+  })
+}
+`
+	check := func(*terraform.State) error {
+		pod, ok := server.Pod("", "/test/pod")
+		if !ok {
+			return fmt.Errorf("Pod was not created")
+		}
+		if containers, _ := pod["containers"].([]interface{}); len(containers) != 1 {
+			return fmt.Errorf("Unexpected containers %v", pod["containers"])
+		}
+		return nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  check,
+			},
+			{
+				PreConfig: func() {
+					server.RemovePod("", "/test/pod")
+				},
+				Config: config,
+				Check:  check,
+			},
+		},
+	})
+}
+
+/** Test reporting the container terminations of a failed pod deployment */
+func TestDcosMarathonPod_deploymentFailure(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+	server.FailDeployments("", "/test/pod", "Command exited with status 1")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + `
+resource "dcos_marathon_pod" "test" {
+  name = "/test/pod"
+
+  container {
+    name = "sleep"
+    exec {
+      command_shell = "exit 1"
+    }
+    resources {
+      cpus = 0.1
+      mem  = 32
+    }
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)deployment_failed.*Container test_pod\.instance-[^ ]+\.sleep \(TASK_FAILED\) exited with code 1: Command exited with status 1`),
+			},
+		},
+	})
+}
+
+/** Test replacing the pod instances in batches when the replace_triggers change */
+func TestDcosMarathonPod_replaceTriggers(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := server.ProviderConfig() + `
+resource "dcos_marathon_pod" "test" {
+  name = "/test/pod"
+
+  scaling {
+    kind      = "fixed"
+    instances = 3
+  }
+
+  container {
+    name = "sleep"
+    exec {
+      command_shell = "sleep 3600"
+    }
+    resources {
+      cpus = 0.1
+      mem  = 32
+    }
+  }
+
+  replace_batch_size = 2
+  replace_triggers = {
+    maintenance = "%s"
+  }
+}
+`
+
+	var instances map[string]string
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "2019-10-01"),
+				Check: func(*terraform.State) error {
+					instances = server.PodInstances("", "/test/pod")
+					if len(instances) != 3 {
+						return fmt.Errorf("Expected 3 instances, got %v", instances)
+					}
+					return nil
+				},
+			},
+			{
+				Config: fmt.Sprintf(config, "2019-10-02"),
+				Check: func(*terraform.State) error {
+					replaced := server.PodInstances("", "/test/pod")
+					for id, state := range replaced {
+						if _, ok := instances[id]; ok {
+							return fmt.Errorf("Expected instance %s to be replaced", id)
+						}
+						if state != "STABLE" {
+							return fmt.Errorf("Expected instance %s to be stable, got %s", id, state)
+						}
+					}
+
+					batches := 0
+					for _, request := range server.Requests() {
+						if request == "DELETE "+testserver.DefaultMarathonPath+"/v2/pods/test/pod::instances" {
+							batches++
+						}
+					}
+					if batches != 2 {
+						return fmt.Errorf("Expected the instances to be replaced in 2 batches, got %d", batches)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+/**
+ * Test replacing the pod instances kept by a scaling change deployed together
+ * with new replace_triggers
+ */
+func TestDcosMarathonPod_replaceTriggersWithScaling(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := server.ProviderConfig() + `
+resource "dcos_marathon_pod" "test" {
+  name = "/test/pod"
+
+  scaling {
+    kind      = "fixed"
+    instances = %d
+  }
+
+  container {
+    name = "sleep"
+    exec {
+      command_shell = "sleep 3600"
+    }
+    resources {
+      cpus = 0.1
+      mem  = 32
+    }
+  }
+
+  replace_batch_size = 2
+  replace_triggers = {
+    maintenance = "%s"
+  }
+}
+`
+
+	var instances map[string]string
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, 3, "2019-10-01"),
+				Check: func(*terraform.State) error {
+					instances = server.PodInstances("", "/test/pod")
+					if len(instances) != 3 {
+						return fmt.Errorf("Expected 3 instances, got %v", instances)
+					}
+					return nil
+				},
+			},
+			{
+				Config: fmt.Sprintf(config, 4, "2019-10-02"),
+				Check: func(*terraform.State) error {
+					replaced := server.PodInstances("", "/test/pod")
+					if len(replaced) != 4 {
+						return fmt.Errorf("Expected 4 instances, got %v", replaced)
+					}
+					for id, state := range replaced {
+						if _, ok := instances[id]; ok {
+							return fmt.Errorf("Expected instance %s to be replaced", id)
+						}
+						if state != "STABLE" {
+							return fmt.Errorf("Expected instance %s to be stable, got %s", id, state)
+						}
+					}
+
+					batches := 0
+					for _, request := range server.Requests() {
+						if request == "DELETE "+testserver.DefaultMarathonPath+"/v2/pods/test/pod::instances" {
+							batches++
+						}
+					}
+					if batches != 2 {
+						return fmt.Errorf("Expected the kept instances to be replaced in 2 batches, got %d", batches)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
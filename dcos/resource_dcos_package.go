@@ -112,7 +112,7 @@ func dcosPackageParseID(appId string) (string, error) {
 func schemaInPackageConfigSpecWithDiffSup() *schema.Schema {
 	baseSchema := schemaInPackageConfigSpec(true)
 	baseSchema.DiffSuppressFunc = func(k, old, new string, d *schema.ResourceData) bool {
-		log.Printf("[TRACE] Comparing old %s '%s' <== with new ==> '%s'", k, old, new)
+		log.Printf("[TRACE] Comparing old %s '%s' <== with new ==> '%s'", k, util.RedactBody("", []byte(old)), util.RedactBody("", []byte(new)))
 		switch k {
 		case "config.%":
 			return false
@@ -133,20 +133,20 @@ func schemaInPackageConfigSpecWithDiffSup() *schema.Schema {
 			savedMap := make(map[string]interface{})
 			err := json.Unmarshal([]byte(old), &savedMap)
 			if err != nil {
-				log.Printf("[WARN] Unable to parse old package config: %s", old)
+				log.Printf("[WARN] Unable to parse old package config: %s", util.RedactBody("", []byte(old)))
 				return false
 			}
 			userMap := make(map[string]interface{})
 			err = json.Unmarshal([]byte(new), &userMap)
 			if err != nil {
-				log.Printf("[WARN] Unable to parse new package config: %s", new)
+				log.Printf("[WARN] Unable to parse new package config: %s", util.RedactBody("", []byte(new)))
 				return false
 			}
 
 			// Check if whatever options are given from the new configuration are actually
 			// changing something on the saved map.
 			diff := util.GetDictDiff(savedMap, userMap)
-			log.Printf("[DEBUG] Delta between saved and user map: %s", util.PrintJSON(diff))
+			log.Printf("[DEBUG] Delta between saved and user map: %s", util.PrintJSON(util.RedactJSON(diff)))
 			if len(diff) == 0 {
 				return true
 			}
@@ -256,9 +256,7 @@ func getPackageSpecFromServiceDesc(desc *dcos.CosmosServiceDescribeV1Response) *
  * app ID. This response includes the package-specific details typically obtained
  * via getPackageDesc.
  */
-func getServiceDesc(client *dcos.APIClient, appId string) (*dcos.CosmosServiceDescribeV1Response, error) {
-	ctx := context.TODO()
-
+func getServiceDesc(ctx context.Context, client *dcos.APIClient, appId string) (*dcos.CosmosServiceDescribeV1Response, error) {
 	describeOpts := &dcos.ServiceDescribeOpts{
 		CosmosServiceDescribeV1Request: optional.NewInterface(dcos.CosmosServiceDescribeV1Request{
 			AppId: appId,
@@ -283,7 +281,7 @@ func getServiceDesc(client *dcos.APIClient, appId string) (*dcos.CosmosServiceDe
 		return nil, fmt.Errorf(util.GetVerboseCosmosError(err, resp))
 	}
 
-	log.Printf("[TRACE] Got service description: %v, %d", pkg.ResolvedOptions, len(pkg.ResolvedOptions))
+	log.Printf("[TRACE] Got service description: %v, %d", util.RedactJSON(pkg.ResolvedOptions), len(pkg.ResolvedOptions))
 	if len(pkg.ResolvedOptions) > 0 {
 		return &pkg, nil
 	}
@@ -296,11 +294,11 @@ func getServiceDesc(client *dcos.APIClient, appId string) (*dcos.CosmosServiceDe
  * waitAndgetServiceDesc gets the service description with the specified app ID,
  * and waits up to <timeountMin> minutes until it's ready.
  */
-func waitAndgetServiceDesc(client *dcos.APIClient, appId string, timeout time.Duration) (*dcos.CosmosServiceDescribeV1Response, error) {
+func waitAndgetServiceDesc(ctx context.Context, client *dcos.APIClient, appId string, timeout time.Duration) (*dcos.CosmosServiceDescribeV1Response, error) {
 	var describeResult *dcos.CosmosServiceDescribeV1Response
 
-	err := resource.Retry(timeout, func() *resource.RetryError {
-		pkg, err := getServiceDesc(client, appId)
+	err := util.RetryContext(ctx, timeout, func() *resource.RetryError {
+		pkg, err := getServiceDesc(ctx, client, appId)
 		if err != nil {
 			log.Printf("[WARN] Breaking out of retry loop because of unrecoverable error")
 			return resource.NonRetryableError(err)
@@ -325,13 +323,21 @@ func waitAndgetServiceDesc(client *dcos.APIClient, appId string, timeout time.Du
  * waitForSDKPlan keeps querying for the plan specified and waits until it enters
  * the given status, or the timeout event occurs.
  */
-func waitForSDKPlan(client *dcos.APIClient, appId string, planName string, waitStatus string, timeout time.Duration) error {
-	sdkClient := util.CreateSDKAPIClient(client, appId)
+func waitForSDKPlan(ctx context.Context, client *dcos.APIClient, appId string, planName string, waitStatus string, timeout time.Duration) error {
+	return waitForSDKPlanPath(ctx, client, appId, fmt.Sprintf("v1/plans/%s", planName), waitStatus, timeout)
+}
 
-	return resource.Retry(timeout, func() *resource.RetryError {
-		plan, err := sdkClient.PlanGetStatus(planName)
+/**
+ * waitForSDKPlanPath works like waitForSDKPlan, but for a plan served on an
+ * arbitrary endpoint of the service
+ */
+func waitForSDKPlanPath(ctx context.Context, client *dcos.APIClient, appId string, planPath string, waitStatus string, timeout time.Duration) error {
+	sdkClient := util.CreateSDKAPIClient(ctx, client, appId)
+
+	return util.RetryContext(ctx, timeout, func() *resource.RetryError {
+		plan, err := sdkClient.PlanGetStatusByPath(planPath)
 		if err != nil {
-			log.Printf("[WARN] Error querying plan %s status: %s", planName, err.Error())
+			log.Printf("[WARN] Error querying plan %s status: %s", planPath, err.Error())
 			return resource.RetryableError(
 				fmt.Errorf("Service %s is not yet responding", appId),
 			)
@@ -341,7 +347,7 @@ func waitForSDKPlan(client *dcos.APIClient, appId string, planName string, waitS
 		if plan.Status != waitStatus {
 			return resource.RetryableError(fmt.Errorf(
 				"Plan %s of service '%s' is '%s' (expecting '%s')",
-				planName, appId, plan.Status, waitStatus,
+				planPath, appId, plan.Status, waitStatus,
 			))
 		}
 
@@ -352,16 +358,16 @@ func waitForSDKPlan(client *dcos.APIClient, appId string, planName string, waitS
 /**
  * Contacts marathon and collects task information about the given app ID
  */
-func getMarathonAppStatus(client *dcos.APIClient, appId string) (*LightMarathonAppInfo, error) {
+func getMarathonAppStatus(ctx context.Context, client *dcos.APIClient, appId string) (*LightMarathonAppInfo, error) {
 	config := client.CurrentDCOSConfig()
 	url := fmt.Sprintf("%s/marathon/v2/apps/%s", config.URL(), appId)
 
-	request, err := http.NewRequest("GET", url, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to prepare request: %s", err.Error())
 	}
 
-	request.Header.Add("Authorization", config.ACSToken())
+	request.Header.Add("Authorization", fmt.Sprintf("token=%s", util.ACSToken(client)))
 	response, err := client.HTTPClient().Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to place request: %s", err.Error())
@@ -394,16 +400,16 @@ func getMarathonAppStatus(client *dcos.APIClient, appId string) (*LightMarathonA
 /**
  * Force-restart a marathon app
  */
-func restartMarathonApp(client *dcos.APIClient, appId string) error {
+func restartMarathonApp(ctx context.Context, client *dcos.APIClient, appId string) error {
 	config := client.CurrentDCOSConfig()
 	url := fmt.Sprintf("%s/marathon/v2/apps/%s/restart?force=true", config.URL(), appId)
 
-	request, err := http.NewRequest("POST", url, nil)
+	request, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
 		return fmt.Errorf("Unable to prepare request: %s", err.Error())
 	}
 
-	request.Header.Add("Authorization", config.ACSToken())
+	request.Header.Add("Authorization", fmt.Sprintf("token=%s", util.ACSToken(client)))
 	response, err := client.HTTPClient().Do(request)
 	if err != nil {
 		return fmt.Errorf("Unable to place request: %s", err.Error())
@@ -420,11 +426,11 @@ func restartMarathonApp(client *dcos.APIClient, appId string) error {
 /**
  * Waits until the given marathon app is up and healthy
  */
-func waitForHealthyMarathonApp(client *dcos.APIClient, appId string, timeout time.Duration) error {
+func waitForHealthyMarathonApp(ctx context.Context, client *dcos.APIClient, appId string, timeout time.Duration) error {
 	log.Printf("[TRACE] Waiting until app %s is healthy", appId)
 
-	return resource.Retry(timeout, func() *resource.RetryError {
-		status, err := getMarathonAppStatus(client, appId)
+	return util.RetryContext(ctx, timeout, func() *resource.RetryError {
+		status, err := getMarathonAppStatus(ctx, client, appId)
 		if err != nil {
 			log.Printf("[WARN] Error querying task %s status: %s", appId, err.Error())
 			return resource.RetryableError(
@@ -480,9 +486,7 @@ func waitForHealthyMarathonApp(client *dcos.APIClient, appId string, timeout tim
  * and returns the package details.
  * `packageVersion` can be blank if you are querying for the latest version.
  */
-func getPackageDesc(client *dcos.APIClient, packageName string, packageVersion string) (*dcos.CosmosPackage, error) {
-	ctx := context.TODO()
-
+func getPackageDesc(ctx context.Context, client *dcos.APIClient, packageName string, packageVersion string) (*dcos.CosmosPackage, error) {
 	// Get the installed versions
 	localVarOptionals := &dcos.PackageDescribeOpts{
 		CosmosPackageDescribeV1Request: optional.NewInterface(dcos.CosmosPackageDescribeV1Request{
@@ -513,7 +517,8 @@ func getPackageDesc(client *dcos.APIClient, packageName string, packageVersion s
  */
 func resourceDcosPackageCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	packageVersion, configCsum, packageConfig, err := collectPackageConfiguration(d.Get("config").(map[string]interface{}))
 	if err != nil {
@@ -521,9 +526,9 @@ func resourceDcosPackageCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	// First, make sure the package exists on the cosmos registry
-	_, err = getPackageDesc(client, packageVersion.Name, packageVersion.Version)
+	_, err = getPackageDesc(ctx, client, packageVersion.Name, packageVersion.Version)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "describing package "+packageVersion.Name, err)
 	}
 
 	// Then check if a similar application already exists
@@ -543,17 +548,17 @@ func resourceDcosPackageCreate(d *schema.ResourceData, meta interface{}) error {
 	cosmosPackageInstallV1Request.Options = util.NestedToFlatMap(packageConfig)
 
 	log.Printf("[DEBUG] Installing package %s:%s using cosmos", packageVersion.Name, packageVersion.Version)
-	log.Printf("[DEBUG] Using options: %s", util.PrintJSON(cosmosPackageInstallV1Request.Options))
+	log.Printf("[DEBUG] Using options: %s", util.PrintJSON(util.RedactJSON(cosmosPackageInstallV1Request.Options)))
 	installedPkg, httpResp, err := client.Cosmos.PackageInstall(ctx, cosmosPackageInstallV1Request)
 	log.Printf("[TRACE] HTTP Response: %v", httpResp)
 
 	if err != nil {
 		log.Printf("[WARN] Cosmos install error: %s", err.Error())
-		return fmt.Errorf("Unable to install package %s:%s: %s",
+		return util.StepError(ctx, "installing package "+packageVersion.Name, fmt.Errorf("Unable to install package %s:%s: %s",
 			packageVersion.Name,
 			packageVersion.Version,
 			util.GetVerboseCosmosError(err, httpResp),
-		)
+		))
 	}
 	log.Printf("[DEBUG] Installed Package: %v", installedPkg)
 
@@ -567,21 +572,21 @@ func resourceDcosPackageCreate(d *schema.ResourceData, meta interface{}) error {
 			return fmt.Errorf("Unable to parse wait duration")
 		}
 
-		_, err = waitAndgetServiceDesc(client, installedPkg.AppId, waitDuration)
+		_, err = waitAndgetServiceDesc(ctx, client, installedPkg.AppId, waitDuration)
 		if err != nil {
-			return fmt.Errorf("Error while waiting for the app to become available: %s", err.Error())
+			return util.StepError(ctx, "waiting for the app to become available", fmt.Errorf("Error while waiting for the app to become available: %s", err.Error()))
 		}
 
 		// If this is an SDK service, also wait for the deployment plan to be completed
 		if d.Get("sdk").(bool) {
-			err = waitForSDKPlan(client, appId, "deploy", "COMPLETE", waitDuration)
+			err = waitForSDKPlan(ctx, client, appId, "deploy", "COMPLETE", waitDuration)
 			if err != nil {
-				return fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error())
+				return util.StepError(ctx, "waiting for the deployment plan", fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error()))
 			}
 		} else {
-			err = waitForHealthyMarathonApp(client, appId, waitDuration)
+			err = waitForHealthyMarathonApp(ctx, client, appId, waitDuration)
 			if err != nil {
-				return fmt.Errorf("Error while waiting for the deployment complete: %s", err.Error())
+				return util.StepError(ctx, "waiting for the deployment", fmt.Errorf("Error while waiting for the deployment complete: %s", err.Error()))
 			}
 		}
 	}
@@ -592,7 +597,7 @@ func resourceDcosPackageCreate(d *schema.ResourceData, meta interface{}) error {
 	if d.Get("sdk").(bool) {
 
 		// Keep track of a configuration ID in the meta-data store of the service
-		sdkClient := util.CreateSDKAPIClient(client, appId)
+		sdkClient := util.CreateSDKAPIClient(ctx, client, appId)
 		_ = sdkClient.SetMeta("csum", configCsum)
 
 		d.SetId(fmt.Sprintf("%s:%s", packageVersion.Name, installedPkg.AppId))
@@ -611,6 +616,8 @@ func resourceDcosPackageRead(d *schema.ResourceData, meta interface{}) error {
 	var err error
 	var desc *dcos.CosmosServiceDescribeV1Response
 	client := meta.(*dcos.APIClient)
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	appId, err := dcosPackageParseID(d.Id())
 
@@ -621,14 +628,14 @@ func resourceDcosPackageRead(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[TRACE] READ Lifecycle - app %s", appId)
 
-	sdkClient := util.CreateSDKAPIClient(client, appId)
+	sdkClient := util.CreateSDKAPIClient(ctx, client, appId)
 
 	// We are going to wait for 5 minutes for the app to appear, just in case we
 	// were very quick on the previous deployment
-	desc, err = getServiceDesc(client, appId)
+	desc, err = getServiceDesc(ctx, client, appId)
 
 	if err != nil {
-		return fmt.Errorf("Error while querying app status: %s", err.Error())
+		return util.StepError(ctx, "querying app status", fmt.Errorf("Error while querying app status: %s", err.Error()))
 	}
 
 	if desc == nil {
@@ -645,7 +652,7 @@ func resourceDcosPackageRead(d *schema.ResourceData, meta interface{}) error {
 
 		v, err := sdkClient.GetMeta("csum", "")
 		if err != nil {
-			return fmt.Errorf("Error fetching old config checksum: %s", err.Error())
+			return util.StepError(ctx, "fetching the config checksum", fmt.Errorf("Error fetching old config checksum: %s", err.Error()))
 		}
 		csum = v.(string)
 	}
@@ -681,10 +688,11 @@ func resourceDcosPackageRead(d *schema.ResourceData, meta interface{}) error {
 func resourceDcosPackageUpdate(d *schema.ResourceData, meta interface{}) error {
 	var desc *dcos.CosmosServiceDescribeV1Response
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	appId := stripRootSlash(d.Get("app_id").(string))
-	sdkClient := util.CreateSDKAPIClient(client, appId)
+	sdkClient := util.CreateSDKAPIClient(ctx, client, appId)
 	log.Printf("[TRACE] UPDATE Lifecycle - app %s", appId)
 
 	waitDuration, err := time.ParseDuration(d.Get("wait_duration").(string))
@@ -732,13 +740,13 @@ func resourceDcosPackageUpdate(d *schema.ResourceData, meta interface{}) error {
 			// We are going to wait for 5 minutes for the app to appear, just in case we
 			// were very quick on the previous deployment
 			if d.Get("wait").(bool) {
-				desc, errQ = waitAndgetServiceDesc(client, appId, waitDuration)
+				desc, errQ = waitAndgetServiceDesc(ctx, client, appId, waitDuration)
 			} else {
-				desc, errQ = getServiceDesc(client, appId)
+				desc, errQ = getServiceDesc(ctx, client, appId)
 			}
 
 			if errQ != nil {
-				return fmt.Errorf("Error while querying app status: %s", errQ.Error())
+				return util.StepError(ctx, "querying app status", fmt.Errorf("Error while querying app status: %s", errQ.Error()))
 			}
 			if desc == nil {
 				return fmt.Errorf("App '%s' was not available. Consider using `wait=true`", appId)
@@ -798,24 +806,24 @@ func resourceDcosPackageUpdate(d *schema.ResourceData, meta interface{}) error {
 			updateServiceName(cosmosServiceUpdateV1Request.Options, appId)
 
 			log.Printf("[DEBUG] Updating package %s:%s to version %s using cosmos", oldVer.Name, oldVer.Version, newVer.Version)
-			log.Printf("[DEBUG] Using options: %s", util.PrintJSON(cosmosServiceUpdateV1Request.Options))
+			log.Printf("[DEBUG] Using options: %s", util.PrintJSON(util.RedactJSON(cosmosServiceUpdateV1Request.Options)))
 			_, httpResp, err := client.Cosmos.ServiceUpdate(ctx, cosmosServiceUpdateV1Request)
 			log.Printf("[TRACE] HTTP Response: %v", httpResp)
 			if err != nil {
-				return fmt.Errorf("Unable to update package %s: %s", appId, util.GetVerboseCosmosError(err, httpResp))
+				return util.StepError(ctx, "updating package "+appId, fmt.Errorf("Unable to update package %s: %s", appId, util.GetVerboseCosmosError(err, httpResp)))
 			}
 
 			// If this is an SDK service, also wait for the deployment plan to be completed
 			if d.Get("wait").(bool) {
 				if d.Get("sdk").(bool) {
-					err = waitForSDKPlan(client, appId, "deploy", "COMPLETE", waitDuration)
+					err = waitForSDKPlan(ctx, client, appId, "deploy", "COMPLETE", waitDuration)
 					if err != nil {
-						return fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error())
+						return util.StepError(ctx, "waiting for the deployment plan", fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error()))
 					}
 				} else {
-					err = waitForHealthyMarathonApp(client, appId, waitDuration)
+					err = waitForHealthyMarathonApp(ctx, client, appId, waitDuration)
 					if err != nil {
-						return fmt.Errorf("Error while waiting for the deployment complete: %s", err.Error())
+						return util.StepError(ctx, "waiting for the deployment", fmt.Errorf("Error while waiting for the deployment complete: %s", err.Error()))
 					}
 				}
 			}
@@ -835,24 +843,24 @@ func resourceDcosPackageUpdate(d *schema.ResourceData, meta interface{}) error {
 			updateServiceName(cosmosServiceUpdateV1Request.Options, appId)
 
 			log.Printf("[DEBUG] Updating package %s:%s configuration using cosmos", oldVer.Name, oldVer.Version)
-			log.Printf("[DEBUG] Using options: %s", util.PrintJSON(cosmosServiceUpdateV1Request.Options))
+			log.Printf("[DEBUG] Using options: %s", util.PrintJSON(util.RedactJSON(cosmosServiceUpdateV1Request.Options)))
 			_, httpResp, err := client.Cosmos.ServiceUpdate(ctx, cosmosServiceUpdateV1Request)
 			log.Printf("[TRACE] HTTP Response: %v", httpResp)
 			if err != nil {
-				return fmt.Errorf("Unable to update service %s: %s", appId, util.GetVerboseCosmosError(err, httpResp))
+				return util.StepError(ctx, "updating service "+appId, fmt.Errorf("Unable to update service %s: %s", appId, util.GetVerboseCosmosError(err, httpResp)))
 			}
 
 			// If this is an SDK service, also wait for the deployment plan to be completed
 			if d.Get("wait").(bool) {
 				if d.Get("sdk").(bool) {
-					err = waitForSDKPlan(client, appId, "deploy", "COMPLETE", waitDuration)
+					err = waitForSDKPlan(ctx, client, appId, "deploy", "COMPLETE", waitDuration)
 					if err != nil {
-						return fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error())
+						return util.StepError(ctx, "waiting for the deployment plan", fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error()))
 					}
 				} else {
-					err = waitForHealthyMarathonApp(client, appId, waitDuration)
+					err = waitForHealthyMarathonApp(ctx, client, appId, waitDuration)
 					if err != nil {
-						return fmt.Errorf("Error while waiting for the deployment complete: %s", err.Error())
+						return util.StepError(ctx, "waiting for the deployment", fmt.Errorf("Error while waiting for the deployment complete: %s", err.Error()))
 					}
 				}
 			}
@@ -865,26 +873,26 @@ func resourceDcosPackageUpdate(d *schema.ResourceData, meta interface{}) error {
 			if d.Get("sdk").(bool) {
 				err := sdkClient.PlanRestart("deploy")
 				if err != nil {
-					return fmt.Errorf("Unable to restart 'deploy' plan: %s", err.Error())
+					return util.StepError(ctx, "restarting the deploy plan", fmt.Errorf("Unable to restart 'deploy' plan: %s", err.Error()))
 				}
 
 				// If we should wait for the plan to be completed, do it now
 				if d.Get("wait").(bool) {
-					err = waitForSDKPlan(client, appId, "deploy", "COMPLETE", waitDuration)
+					err = waitForSDKPlan(ctx, client, appId, "deploy", "COMPLETE", waitDuration)
 					if err != nil {
-						return fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error())
+						return util.StepError(ctx, "waiting for the deployment plan", fmt.Errorf("Error while waiting for the deployment plan to complete: %s", err.Error()))
 					}
 				}
 
 			} else {
-				err = restartMarathonApp(client, appId)
+				err = restartMarathonApp(ctx, client, appId)
 				if err != nil {
-					return fmt.Errorf("Error while restarting marathon app: %s", err.Error())
+					return util.StepError(ctx, "restarting the marathon app", fmt.Errorf("Error while restarting marathon app: %s", err.Error()))
 				}
 
-				err = waitForHealthyMarathonApp(client, appId, waitDuration)
+				err = waitForHealthyMarathonApp(ctx, client, appId, waitDuration)
 				if err != nil {
-					return fmt.Errorf("Error while waiting for the deployment complete: %s", err.Error())
+					return util.StepError(ctx, "waiting for the deployment", fmt.Errorf("Error while waiting for the deployment complete: %s", err.Error()))
 				}
 			}
 
@@ -909,12 +917,13 @@ func resourceDcosPackageUpdate(d *schema.ResourceData, meta interface{}) error {
  */
 func resourceDcosPackageDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 	appId := stripRootSlash(d.Get("app_id").(string))
 	log.Printf("[TRACE] DELETE Lifecycle - app %s", appId)
 
 	// We are going to get reaped by the SDK uninstall, but just in case
-	sdkClient := util.CreateSDKAPIClient(client, appId)
+	sdkClient := util.CreateSDKAPIClient(ctx, client, appId)
 	_ = sdkClient.SetMeta("csum", "")
 
 	packageVersion, _, _, err := collectPackageConfiguration(d.Get("config").(map[string]interface{}))
@@ -931,7 +940,7 @@ func resourceDcosPackageDelete(d *schema.ResourceData, meta interface{}) error {
 	_, resp, err := client.Cosmos.PackageUninstall(ctx, cosmosPackageUninstallV1Request, nil)
 	log.Printf("[TRACE] Cosmos.PackageUninstall - %v", resp)
 	if err != nil {
-		return fmt.Errorf("Unable to uninstall package: %s", util.GetVerboseCosmosError(err, resp))
+		return util.StepError(ctx, "uninstalling package "+appId, fmt.Errorf("Unable to uninstall package: %s", util.GetVerboseCosmosError(err, resp)))
 	}
 
 	// If instructed, wait until it no loger appears on the enumeration
@@ -943,7 +952,7 @@ func resourceDcosPackageDelete(d *schema.ResourceData, meta interface{}) error {
 				PackageName: packageVersion.Name,
 			}),
 		}
-		err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		err := util.RetryContext(ctx, 5*time.Minute, func() *resource.RetryError {
 			lst, resp, err := client.Cosmos.PackageList(ctx, listOpts)
 			log.Printf("[TRACE] Cosmos.PackageList - %v, lst: %#v", resp, lst)
 			if err != nil {
@@ -959,7 +968,7 @@ func resourceDcosPackageDelete(d *schema.ResourceData, meta interface{}) error {
 			return resource.RetryableError(fmt.Errorf("appId %s still uninstalling", appId))
 		})
 		if err != nil {
-			return fmt.Errorf("Error while waiting for the service to be uninstalled: %s", err.Error())
+			return util.StepError(ctx, "waiting for the service to be uninstalled", fmt.Errorf("Error while waiting for the service to be uninstalled: %s", err.Error()))
 		}
 	}
 
@@ -1,7 +1,6 @@
 package dcos
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -10,6 +9,7 @@ import (
 	"github.com/antihax/optional"
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosPackageRepo() *schema.Resource {
@@ -60,7 +60,8 @@ func resourceDcosPackageRepo() *schema.Resource {
 
 func resourceDcosPackageRepoCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 	log.Println("[DEBUG] Creating package repository")
 
 	index := d.Get("index").(int)
@@ -96,7 +97,7 @@ func resourceDcosPackageRepoCreate(d *schema.ResourceData, meta interface{}) err
 			}
 		}
 
-		return fmt.Errorf("Unable to place a repository add request: %s", err.Error())
+		return util.StepError(ctx, "adding repository "+repoName, fmt.Errorf("Unable to place a repository add request: %s", err.Error()))
 	}
 
 	// As the "ID" we are using the name/URL combo, separated with a character
@@ -108,12 +109,13 @@ func resourceDcosPackageRepoCreate(d *schema.ResourceData, meta interface{}) err
 
 func resourceDcosPackageRepoRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 	log.Printf("[DEBUG] Reading package repository: id='%s'", d.Id())
 
 	resp, _, err := client.Cosmos.PackageRepositoryList(ctx, make(map[string]interface{}))
 	if err != nil {
-		return fmt.Errorf("Unable to enumerate repositories: %s", err.Error())
+		return util.StepError(ctx, "listing the package repositories", fmt.Errorf("Unable to enumerate repositories: %s", err.Error()))
 	}
 
 	// Separate Name/URL from the ID
@@ -151,7 +153,8 @@ func resourceDcosPackageRepoRead(d *schema.ResourceData, meta interface{}) error
 
 func resourceDcosPackageRepoDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 	log.Println("[DEBUG] Deleting package repository")
 
 	// Separate Name/URL from the ID
@@ -177,7 +180,7 @@ func resourceDcosPackageRepoDelete(d *schema.ResourceData, meta interface{}) err
 					}
 				}
 			}
-			return fmt.Errorf("Unable to delete the repository: %s", err.Error())
+			return util.StepError(ctx, "deleting repository "+nameUri[0], fmt.Errorf("Unable to delete the repository: %s", err.Error()))
 		}
 
 	}
@@ -0,0 +1,52 @@
+package dcos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test adding and removing a package repository against the test server */
+func TestDcosPackageRepo_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	hasRepo := func(name string) bool {
+		for _, repo := range server.Repositories() {
+			if repo.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if hasRepo("local") {
+				return fmt.Errorf("Repository still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_package_repo" "test" {
+  name     = "local"
+  url      = %q
+  volatile = true
+}
+`, server.RepositoryURL()),
+				Check: func(*terraform.State) error {
+					if !hasRepo("local") {
+						return fmt.Errorf("Repository was not added")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -1,9 +1,13 @@
 package dcos
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
 )
 
 func TestAccDcosPackage_import(t *testing.T) {
@@ -18,3 +22,89 @@ func TestAccDcosPackage_import(t *testing.T) {
 		},
 	})
 }
+
+/** Test installing and re-configuring an SDK package against the test server */
+func TestDcosPackage_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	server.AddPackage(dcos.CosmosPackage{
+		Name:        "hello-world",
+		Version:     "2.0.0",
+		Description: "Hello World",
+		Maintainer:  "support@mesosphere.io",
+		Config: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{"type": "string", "default": "hello-world"},
+						"cpus": map[string]interface{}{"type": "number", "default": 1},
+					},
+				},
+			},
+		},
+	})
+
+	config := func(cpus int) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+data "dcos_package_version" "hello" {
+  repo_url = %q
+  name     = "hello-world"
+  version  = "2.0.0"
+}
+
+data "dcos_package_config" "hello" {
+  version_spec = data.dcos_package_version.hello.spec
+
+  section {
+    path = "service"
+    map = {
+      cpus = %d
+    }
+  }
+}
+
+resource "dcos_package" "hello" {
+  app_id = "/hello-world"
+  config = data.dcos_package_config.hello.config
+  sdk    = true
+}
+`, server.RepositoryURL(), cpus)
+	}
+
+	checkCpus := func(expected float64) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			_, options, ok := server.InstalledPackage("/hello-world")
+			if !ok {
+				return fmt.Errorf("Package was not installed")
+			}
+			service, _ := options["service"].(map[string]interface{})
+			if service["cpus"] != expected {
+				return fmt.Errorf("Package was installed with options %v", options)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, _, ok := server.InstalledPackage("/hello-world"); ok {
+				return fmt.Errorf("Package is still installed")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(2),
+				Check:  checkCpus(2),
+			},
+			{
+				Config: config(3),
+				Check:  checkCpus(3),
+			},
+		},
+	})
+}
@@ -1,13 +1,13 @@
 package dcos
 
 import (
-	"context"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecurityClusterOIDC() *schema.Resource {
@@ -82,7 +82,8 @@ func resourceDcosSecurityClusterOIDC() *schema.Resource {
 
 func resourceDcosSecurityClusterOIDCCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	providerID := d.Get("provider_id").(string)
 	baseURL := d.Get("base_url").(string)
@@ -113,7 +114,7 @@ func resourceDcosSecurityClusterOIDCCreate(d *schema.ResourceData, meta interfac
 	log.Printf("[TRACE] IAM.ConfigureOIDCProvider - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "configuring OIDC provider "+providerID, err)
 	}
 
 	return resourceDcosSecurityClusterOIDCRead(d, meta)
@@ -121,7 +122,8 @@ func resourceDcosSecurityClusterOIDCCreate(d *schema.ResourceData, meta interfac
 
 func resourceDcosSecurityClusterOIDCRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	providerID := d.Get("provider_id").(string)
 
@@ -133,7 +135,7 @@ func resourceDcosSecurityClusterOIDCRead(d *schema.ResourceData, meta interface{
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading OIDC provider "+providerID, err)
 	}
 
 	d.Set("description", providerConfig.Description)
@@ -153,7 +155,8 @@ func resourceDcosSecurityClusterOIDCRead(d *schema.ResourceData, meta interface{
 
 func resourceDcosSecurityClusterOIDCUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	var iamoidcProviderConfig dcos.IamoidcProviderConfig
 
@@ -176,7 +179,7 @@ func resourceDcosSecurityClusterOIDCUpdate(d *schema.ResourceData, meta interfac
 	log.Printf("[TRACE] IAM.UpdateOIDCProvider - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating OIDC provider "+providerID, err)
 	}
 
 	return resourceDcosSecurityClusterOIDCRead(d, meta)
@@ -184,7 +187,8 @@ func resourceDcosSecurityClusterOIDCUpdate(d *schema.ResourceData, meta interfac
 
 func resourceDcosSecurityClusterOIDCDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	providerID := d.Get("provider_id").(string)
 
@@ -193,7 +197,7 @@ func resourceDcosSecurityClusterOIDCDelete(d *schema.ResourceData, meta interfac
 	log.Printf("[TRACE] IAM.DeleteOIDCProvider - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting OIDC provider "+providerID, err)
 	}
 
 	d.SetId("")
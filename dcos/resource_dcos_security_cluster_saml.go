@@ -1,7 +1,6 @@
 package dcos
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +11,7 @@ import (
 	"github.com/beevik/etree"
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecurityClusterSAML() *schema.Resource {
@@ -92,7 +92,8 @@ func validateProviderID(i interface{}, k string) (s []string, es []error) {
 
 func resourceDcosSecurityClusterSAMLCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	providerId := d.Get("provider_id").(string)
 	idpMetadata := d.Get("idp_metadata").(string)
@@ -111,7 +112,7 @@ func resourceDcosSecurityClusterSAMLCreate(d *schema.ResourceData, meta interfac
 	log.Printf("[TRACE] IAM.ConfigureSAMLProvider - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "configuring SAML provider "+providerId, err)
 	}
 
 	return resourceDcosSecurityClusterSAMLRead(d, meta)
@@ -119,7 +120,8 @@ func resourceDcosSecurityClusterSAMLCreate(d *schema.ResourceData, meta interfac
 
 func resourceDcosSecurityClusterSAMLRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	providerId := d.Get("provider_id").(string)
 	providerConfig, resp, err := client.IAM.GetSAMLProvider(ctx, providerId)
@@ -130,7 +132,7 @@ func resourceDcosSecurityClusterSAMLRead(d *schema.ResourceData, meta interface{
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading SAML provider "+providerId, err)
 	}
 
 	d.Set("description", providerConfig.Description)
@@ -144,9 +146,16 @@ func resourceDcosSecurityClusterSAMLRead(d *schema.ResourceData, meta interface{
 	}
 
 	metadata, metadataResp, err := client.IAM.GetSAMLProviderSPMetadata(ctx, providerId)
-	dumpReq, _ := httputil.DumpRequest(metadataResp.Request, false)
-	log.Printf("[TRACE] IAM.GetSAMLProviderSPMetadata - %v", metadataResp)
-	log.Printf("[TRACE] IAM.GetSAMLProviderSPMetadata - Request %s", dumpReq)
+	if metadataResp != nil {
+		dumpReq, _ := httputil.DumpRequest(metadataResp.Request, false)
+		log.Printf("[TRACE] IAM.GetSAMLProviderSPMetadata - %v", metadataResp)
+		log.Printf("[TRACE] IAM.GetSAMLProviderSPMetadata - Request %s", dumpReq)
+	}
+
+	// The meta-data is optional, unless the operation ran out of time
+	if err != nil && ctx.Err() != nil {
+		return util.StepError(ctx, "reading the meta-data of SAML provider "+providerId, err)
+	}
 
 	if err != nil {
 		log.Printf("[WARNING] IAM.GetSAMLProviderSPMetadata Error - %v", err)
@@ -176,7 +185,8 @@ func resourceDcosSecurityClusterSAMLRead(d *schema.ResourceData, meta interface{
 
 func resourceDcosSecurityClusterSAMLUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	var iamsamlProviderConfig dcos.IamsamlProviderConfig
 
@@ -196,7 +206,7 @@ func resourceDcosSecurityClusterSAMLUpdate(d *schema.ResourceData, meta interfac
 	log.Printf("[TRACE] IAM.UpdateSAMLProvider - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating SAML provider "+providerId, err)
 	}
 
 	return resourceDcosSecurityClusterSAMLRead(d, meta)
@@ -204,7 +214,8 @@ func resourceDcosSecurityClusterSAMLUpdate(d *schema.ResourceData, meta interfac
 
 func resourceDcosSecurityClusterSAMLDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	providerId := d.Get("provider_id").(string)
 
@@ -213,7 +224,7 @@ func resourceDcosSecurityClusterSAMLDelete(d *schema.ResourceData, meta interfac
 	log.Printf("[TRACE] IAM.DeleteSAMLProvider - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting SAML provider "+providerId, err)
 	}
 
 	d.SetId("")
@@ -1,7 +1,6 @@
 package dcos
 
 import (
-	"context"
 	"log"
 	"net/http"
 	"time"
@@ -9,6 +8,7 @@ import (
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecurityOrgExternalUser() *schema.Resource {
@@ -57,7 +57,8 @@ func resourceDcosSecurityOrgExternalUser() *schema.Resource {
 
 func resourceDcosSecurityOrgExternalUserCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	uid := d.Get("uid").(string)
 	iamUserCreate, err := iamUserCreateFromResourceData(d)
@@ -71,7 +72,7 @@ func resourceDcosSecurityOrgExternalUserCreate(d *schema.ResourceData, meta inte
 	log.Printf("[TRACE] IAM.CreateUser - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "creating external user "+uid, err)
 	}
 
 	d.SetId(uid)
@@ -81,7 +82,8 @@ func resourceDcosSecurityOrgExternalUserCreate(d *schema.ResourceData, meta inte
 
 func resourceDcosSecurityOrgExternalUserRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	uid := d.Id()
 
@@ -89,14 +91,14 @@ func resourceDcosSecurityOrgExternalUserRead(d *schema.ResourceData, meta interf
 
 	log.Printf("[TRACE] IAM.GetUser - %v", resp)
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		log.Printf("[INFO] IAM.GetUser - %s not found", uid)
 		d.SetId("")
 		return nil
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading external user "+uid, err)
 	}
 
 	d.Set("description", user.Description)
@@ -107,7 +109,8 @@ func resourceDcosSecurityOrgExternalUserRead(d *schema.ResourceData, meta interf
 
 func resourceDcosSecurityOrgExternalUserUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	uid := d.Id()
 	iamUserUpdate := dcos.IamUserUpdate{}
@@ -126,7 +129,7 @@ func resourceDcosSecurityOrgExternalUserUpdate(d *schema.ResourceData, meta inte
 	log.Printf("[TRACE] IAM.UpdateUser - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating external user "+uid, err)
 	}
 
 	return resourceDcosSecurityOrgExternalUserRead(d, meta)
@@ -134,17 +137,18 @@ func resourceDcosSecurityOrgExternalUserUpdate(d *schema.ResourceData, meta inte
 
 func resourceDcosSecurityOrgExternalUserDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	resp, err := client.IAM.DeleteUser(ctx, d.Id())
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting external user "+d.Id(), err)
 	}
 
 	d.SetId("")
@@ -1,7 +1,6 @@
 package dcos
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,6 +8,7 @@ import (
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecurityOrgGroup() *schema.Resource {
@@ -52,7 +52,8 @@ func resourceDcosSecurityOrgGroup() *schema.Resource {
 
 func resourceDcosSecurityOrgGroupCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	gid := d.Get("gid").(string)
 	iamGroupCreate := dcos.IamGroupCreate{}
@@ -65,7 +66,7 @@ func resourceDcosSecurityOrgGroupCreate(d *schema.ResourceData, meta interface{}
 	log.Printf("[TRACE] IAM.CreateGroup - %v", resp)
 
 	if err != nil {
-		return fmt.Errorf("Unable to create group: %s", err.Error())
+		return util.StepError(ctx, "creating group "+gid, fmt.Errorf("Unable to create group: %s", err.Error()))
 	}
 
 	d.SetId(gid)
@@ -75,7 +76,8 @@ func resourceDcosSecurityOrgGroupCreate(d *schema.ResourceData, meta interface{}
 
 func resourceDcosSecurityOrgGroupRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	gid := d.Id()
 
@@ -83,14 +85,14 @@ func resourceDcosSecurityOrgGroupRead(d *schema.ResourceData, meta interface{})
 
 	log.Printf("[TRACE] IAM.GetGroup - %v", resp)
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		log.Printf("[INFO] IAM.GetGroup - %s not found", gid)
 		d.SetId("")
 		return nil
 	}
 
 	if err != nil {
-		return fmt.Errorf("Unable to read group: %s", err.Error())
+		return util.StepError(ctx, "reading group "+gid, fmt.Errorf("Unable to read group: %s", err.Error()))
 	}
 
 	d.Set("description", group.Description)
@@ -101,7 +103,8 @@ func resourceDcosSecurityOrgGroupRead(d *schema.ResourceData, meta interface{})
 
 func resourceDcosSecurityOrgGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	gid := d.Id()
 
@@ -112,7 +115,7 @@ func resourceDcosSecurityOrgGroupUpdate(d *schema.ResourceData, meta interface{}
 
 	_, err := client.IAM.UpdateGroup(ctx, gid, iamGroupUpdate)
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating group "+gid, err)
 	}
 
 	return resourceDcosSecurityOrgGroupRead(d, meta)
@@ -120,17 +123,18 @@ func resourceDcosSecurityOrgGroupUpdate(d *schema.ResourceData, meta interface{}
 
 func resourceDcosSecurityOrgGroupDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	resp, err := client.IAM.DeleteGroup(ctx, d.Id())
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	}
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete group: %s", err.Error())
+		return util.StepError(ctx, "deleting group "+d.Id(), fmt.Errorf("Unable to delete group: %s", err.Error()))
 	}
 
 	d.SetId("")
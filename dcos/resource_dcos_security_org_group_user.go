@@ -1,7 +1,6 @@
 package dcos
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,6 +10,7 @@ import (
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecurityOrgGroupUser() *schema.Resource {
@@ -49,7 +49,8 @@ func resourceDcosSecurityOrgGroupUser() *schema.Resource {
 
 func resourceDcosSecurityOrgGroupUserCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	gid := d.Get("gid").(string)
 	uid := d.Get("uid").(string)
@@ -58,7 +59,7 @@ func resourceDcosSecurityOrgGroupUserCreate(d *schema.ResourceData, meta interfa
 	log.Printf("[TRACE] IAM.CreateGroupUser - %v", resp)
 
 	if err != nil {
-		return fmt.Errorf("Unable to add user %s to group %s: %s", uid, gid, err.Error())
+		return util.StepError(ctx, fmt.Sprintf("adding user %s to group %s", uid, gid), fmt.Errorf("Unable to add user %s to group %s: %s", uid, gid, err.Error()))
 	}
 
 	d.SetId(dcosIAMGroupUsergenID(d))
@@ -86,7 +87,8 @@ func dcosIAMGroupUsergenID(d *schema.ResourceData) string {
 
 func resourceDcosSecurityOrgGroupUserRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	gid := d.Get("gid").(string)
 	uid := d.Get("uid").(string)
@@ -94,24 +96,26 @@ func resourceDcosSecurityOrgGroupUserRead(d *schema.ResourceData, meta interface
 	users, resp, err := client.IAM.GetGroupUsers(ctx, gid, &dcos.GetGroupUsersOpts{})
 	serviceaccounts, serviceaccountsResp, serviceaccountsErr := client.IAM.GetGroupUsers(ctx, gid, &dcos.GetGroupUsersOpts{Type_: optional.NewString("service")})
 
-	if !dcosIAMGroupUserinUserArray(uid, users) && !dcosIAMGroupUserinUserArray(uid, serviceaccounts) {
-		log.Printf("[INFO] IAM.GetGroupUsers - %s not in group %s", uid, gid)
-		d.SetId("")
-		return nil
-	}
-
 	log.Printf("[TRACE] IAM.GetGroupUsers - %v", resp)
 
-	if resp.StatusCode == http.StatusNotFound || serviceaccountsResp.StatusCode == http.StatusNotFound {
+	if (resp != nil && resp.StatusCode == http.StatusNotFound) || (serviceaccountsResp != nil && serviceaccountsResp.StatusCode == http.StatusNotFound) {
 		return fmt.Errorf("[INFO] IAM.GetGroupUsers - %s not found", gid)
 	}
 
+	// Only trust the membership if both listings succeeded
+	step := fmt.Sprintf("reading the members of group %s", gid)
 	if err != nil {
-		return fmt.Errorf("Unable to find user %s in group %s: %s", uid, gid, err.Error())
+		return util.StepError(ctx, step, fmt.Errorf("Unable to find user %s in group %s: %s", uid, gid, err.Error()))
 	}
 
 	if serviceaccountsErr != nil {
-		return fmt.Errorf("Unable to find user %s in group %s: %s", uid, gid, serviceaccountsErr.Error())
+		return util.StepError(ctx, step, fmt.Errorf("Unable to find user %s in group %s: %s", uid, gid, serviceaccountsErr.Error()))
+	}
+
+	if !dcosIAMGroupUserinUserArray(uid, users) && !dcosIAMGroupUserinUserArray(uid, serviceaccounts) {
+		log.Printf("[INFO] IAM.GetGroupUsers - %s not in group %s", uid, gid)
+		d.SetId("")
+		return nil
 	}
 
 	d.SetId(dcosIAMGroupUsergenID(d))
@@ -121,20 +125,21 @@ func resourceDcosSecurityOrgGroupUserRead(d *schema.ResourceData, meta interface
 
 func resourceDcosSecurityOrgGroupUserDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	gid := d.Get("gid").(string)
 	uid := d.Get("uid").(string)
 
 	resp, err := client.IAM.DeleteGroupUser(ctx, gid, uid)
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	}
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete user %s from group %s: %s", uid, gid, err.Error())
+		return util.StepError(ctx, fmt.Sprintf("removing user %s from group %s", uid, gid), fmt.Errorf("Unable to delete user %s from group %s: %s", uid, gid, err.Error()))
 	}
 
 	d.SetId("")
@@ -1,13 +1,13 @@
 package dcos
 
 import (
-	"context"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecurityOrgServiceAccount() *schema.Resource {
@@ -78,7 +78,8 @@ func iamUserCreateFromResourceData(d *schema.ResourceData) (dcos.IamUserCreate,
 
 func resourceDcosSecurityOrgServiceAccountCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	uid := d.Get("uid").(string)
 	iamUserCreate, err := iamUserCreateFromResourceData(d)
@@ -92,7 +93,7 @@ func resourceDcosSecurityOrgServiceAccountCreate(d *schema.ResourceData, meta in
 	log.Printf("[TRACE] IAM.CreateUser - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "creating service account "+uid, err)
 	}
 
 	d.SetId(uid)
@@ -102,7 +103,8 @@ func resourceDcosSecurityOrgServiceAccountCreate(d *schema.ResourceData, meta in
 
 func resourceDcosSecurityOrgServiceAccountRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	uid := d.Id()
 
@@ -117,7 +119,7 @@ func resourceDcosSecurityOrgServiceAccountRead(d *schema.ResourceData, meta inte
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading service account "+uid, err)
 	}
 
 	d.Set("description", user.Description)
@@ -129,7 +131,8 @@ func resourceDcosSecurityOrgServiceAccountRead(d *schema.ResourceData, meta inte
 
 func resourceDcosSecurityOrgServiceAccountUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	uid := d.Id()
 	iamUserUpdate := dcos.IamUserUpdate{}
@@ -143,7 +146,7 @@ func resourceDcosSecurityOrgServiceAccountUpdate(d *schema.ResourceData, meta in
 	log.Printf("[TRACE] IAM.UpdateUser - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating service account "+uid, err)
 	}
 
 	return resourceDcosSecurityOrgServiceAccountRead(d, meta)
@@ -151,7 +154,8 @@ func resourceDcosSecurityOrgServiceAccountUpdate(d *schema.ResourceData, meta in
 
 func resourceDcosSecurityOrgServiceAccountDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	resp, err := client.IAM.DeleteUser(ctx, d.Id())
 
@@ -161,7 +165,7 @@ func resourceDcosSecurityOrgServiceAccountDelete(d *schema.ResourceData, meta in
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting service account "+d.Id(), err)
 	}
 
 	d.SetId("")
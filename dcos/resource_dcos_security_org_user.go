@@ -1,13 +1,13 @@
 package dcos
 
 import (
-	"context"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecurityOrgUser() *schema.Resource {
@@ -51,7 +51,8 @@ func resourceDcosSecurityOrgUser() *schema.Resource {
 
 func resourceDcosSecurityOrgUserCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	uid := d.Get("uid").(string)
 	iamUserCreate, err := iamUserCreateFromResourceData(d)
@@ -65,7 +66,7 @@ func resourceDcosSecurityOrgUserCreate(d *schema.ResourceData, meta interface{})
 	log.Printf("[TRACE] IAM.CreateUser - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "creating user "+uid, err)
 	}
 
 	d.SetId(uid)
@@ -75,7 +76,8 @@ func resourceDcosSecurityOrgUserCreate(d *schema.ResourceData, meta interface{})
 
 func resourceDcosSecurityOrgUserRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	uid := d.Id()
 
@@ -83,14 +85,14 @@ func resourceDcosSecurityOrgUserRead(d *schema.ResourceData, meta interface{}) e
 
 	log.Printf("[TRACE] IAM.GetUser - %v", resp)
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		log.Printf("[INFO] IAM.GetUser - %s not found", uid)
 		d.SetId("")
 		return nil
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "reading user "+uid, err)
 	}
 
 	d.Set("description", user.Description)
@@ -101,7 +103,8 @@ func resourceDcosSecurityOrgUserRead(d *schema.ResourceData, meta interface{}) e
 
 func resourceDcosSecurityOrgUserUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	uid := d.Id()
 	iamUserUpdate := dcos.IamUserUpdate{}
@@ -120,7 +123,7 @@ func resourceDcosSecurityOrgUserUpdate(d *schema.ResourceData, meta interface{})
 	log.Printf("[TRACE] IAM.UpdateUser - %v", resp)
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "updating user "+uid, err)
 	}
 
 	return resourceDcosSecurityOrgUserRead(d, meta)
@@ -128,17 +131,18 @@ func resourceDcosSecurityOrgUserUpdate(d *schema.ResourceData, meta interface{})
 
 func resourceDcosSecurityOrgUserDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	resp, err := client.IAM.DeleteUser(ctx, d.Id())
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		d.SetId("")
 		return nil
 	}
 
 	if err != nil {
-		return err
+		return util.StepError(ctx, "deleting user "+d.Id(), err)
 	}
 
 	d.SetId("")
@@ -1,7 +1,6 @@
 package dcos
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,6 +9,7 @@ import (
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecurityOrgUserGrant() *schema.Resource {
@@ -55,7 +55,8 @@ func resourceDcosSecurityOrgUserGrant() *schema.Resource {
 
 func resourceDcosSecurityOrgUserGrantCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	uid := d.Get("uid").(string)
 	rid := d.Get("resource").(string)
@@ -63,30 +64,30 @@ func resourceDcosSecurityOrgUserGrantCreate(d *schema.ResourceData, meta interfa
 
 	// Ensure that the ACL exists
 	rid = strings.Replace(rid, "/", "%252F", -1)
-	resp, err := client.IAM.CreateResourceACL(context.TODO(), rid, dcos.IamaclCreate{})
+	resp, err := client.IAM.CreateResourceACL(ctx, rid, dcos.IamaclCreate{})
 	if err != nil {
 		if resp == nil || resp.StatusCode != 409 {
-			return fmt.Errorf(
+			return util.StepError(ctx, "creating the ACL "+rid, fmt.Errorf(
 				"Unable to create resource ACL for '%s': %s",
 				rid,
 				err.Error(),
-			)
+			))
 		}
 		log.Printf("permission '%s:%s' for user '%s' already exists", rid, action, uid)
 	}
 
 	// Grant permission
 	resp, err = client.IAM.PermitResourceUserAction(ctx, rid, uid, action)
-	log.Printf("[TRACE] PermitResourceUserAction - %v", resp.Request)
+	log.Printf("[TRACE] PermitResourceUserAction - %v", resp)
 	if err != nil {
 		if resp == nil || resp.StatusCode != 409 {
-			return fmt.Errorf(
+			return util.StepError(ctx, fmt.Sprintf("granting %s on %s to user %s", action, rid, uid), fmt.Errorf(
 				"Unable to grant '%s' action on '%s' resource for user '%s': %s",
 				action,
 				rid,
 				uid,
 				err.Error(),
-			)
+			))
 		}
 		log.Printf("grant '%s' action on '%s' resource for user '%s' already exists", action, rid, uid)
 	}
@@ -111,7 +112,8 @@ func inPermissions(permissions dcos.IamUserPermissions, rid string, action strin
 
 func resourceDcosSecurityOrgUserGrantRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	uid := d.Get("uid").(string)
 	rid := d.Get("resource").(string)
@@ -126,7 +128,7 @@ func resourceDcosSecurityOrgUserGrantRead(d *schema.ResourceData, meta interface
 	}
 
 	if err != nil {
-		return fmt.Errorf("Error while reading permissions: %s", err.Error())
+		return util.StepError(ctx, "reading the permissions of user "+uid, fmt.Errorf("Error while reading permissions: %s", err.Error()))
 	}
 
 	if inPermissions(permissions, rid, action) {
@@ -140,7 +142,8 @@ func resourceDcosSecurityOrgUserGrantRead(d *schema.ResourceData, meta interface
 
 func resourceDcosSecurityOrgUserGrantDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	uid := d.Get("uid").(string)
 	rid := d.Get("resource").(string)
@@ -154,7 +157,7 @@ func resourceDcosSecurityOrgUserGrantDelete(d *schema.ResourceData, meta interfa
 	}
 
 	if err != nil {
-		return fmt.Errorf("Error while revoking permissions: %s", err.Error())
+		return util.StepError(ctx, fmt.Sprintf("revoking %s on %s from user %s", action, rid, uid), fmt.Errorf("Error while revoking permissions: %s", err.Error()))
 	}
 
 	d.SetId("")
@@ -0,0 +1,79 @@
+package dcos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test creating, updating and deleting a user against the test server */
+func TestDcosSecurityOrgUser_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := func(description string) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_security_org_user" "test" {
+  uid         = "alice"
+  description = %q
+  password    = "secret"
+}
+
+resource "dcos_security_org_group" "test" {
+  gid         = "devs"
+  description = "Developers"
+}
+
+resource "dcos_security_org_group_user" "test" {
+  gid = dcos_security_org_group.test.gid
+  uid = dcos_security_org_user.test.uid
+}
+
+resource "dcos_security_org_user_grant" "test" {
+  uid      = dcos_security_org_user.test.uid
+  resource = "dcos:adminrouter:service:marathon"
+  action   = "full"
+}
+`, description)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.User("alice"); ok {
+				return fmt.Errorf("User alice still exists")
+			}
+			if _, ok := server.Group("devs"); ok {
+				return fmt.Errorf("Group devs still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("Alice"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("dcos_security_org_user.test", "description", "Alice"),
+					func(*terraform.State) error {
+						if !server.IsPermitted("alice", "dcos:adminrouter:service:marathon", "full") {
+							return fmt.Errorf("Grant was not created")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: config("Alice Doe"),
+				Check: func(*terraform.State) error {
+					user, ok := server.User("alice")
+					if !ok || user.Description != "Alice Doe" {
+						return fmt.Errorf("User was not updated: %v", user)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -1,7 +1,6 @@
 package dcos
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosSecuritySecret() *schema.Resource {
@@ -59,7 +59,8 @@ func encodePath(pathToSecret string) string {
 
 func resourceDcosSecuritySecretCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+	defer cancel()
 
 	secretsV1Secret := dcos.SecretsV1Secret{}
 	secretsV1Secret.Value = d.Get("value").(string)
@@ -77,10 +78,10 @@ func resourceDcosSecuritySecretCreate(d *schema.ResourceData, meta interface{})
 			resp, err := client.Secrets.UpdateSecret(ctx, store, encodePath(pathToSecret), secretsV1Secret)
 			log.Printf("[TRACE] Update %s, %s - %v", store, pathToSecret, resp)
 			if err != nil {
-				return fmt.Errorf("Unable to update existing secret: %s", err.Error())
+				return util.StepError(ctx, "replacing secret "+pathToSecret, fmt.Errorf("Unable to update existing secret: %s", err.Error()))
 			}
 		} else {
-			return fmt.Errorf("Unable to create secret: %s", err.Error())
+			return util.StepError(ctx, "creating secret "+pathToSecret, fmt.Errorf("Unable to create secret: %s", err.Error()))
 		}
 	}
 
@@ -90,7 +91,8 @@ func resourceDcosSecuritySecretCreate(d *schema.ResourceData, meta interface{})
 
 func resourceDcosSecuritySecretRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutRead)
+	defer cancel()
 
 	store := d.Get("store").(string)
 	pathToSecret := d.Get("path").(string)
@@ -106,7 +108,7 @@ func resourceDcosSecuritySecretRead(d *schema.ResourceData, meta interface{}) er
 	}
 
 	if err != nil {
-		return nil
+		return util.StepError(ctx, "reading secret "+pathToSecret, fmt.Errorf("Unable to read secret: %s", err.Error()))
 	}
 
 	d.Set("value", secret.Value)
@@ -121,7 +123,8 @@ func generateID(store string, pathToSecret string) string {
 
 func resourceDcosSecuritySecretUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutUpdate)
+	defer cancel()
 
 	secretsV1Secret := dcos.SecretsV1Secret{}
 	secretsV1Secret.Value = d.Get("value").(string)
@@ -133,7 +136,7 @@ func resourceDcosSecuritySecretUpdate(d *schema.ResourceData, meta interface{})
 	_, err := client.Secrets.UpdateSecret(ctx, store, encodePath(pathToSecret), secretsV1Secret)
 
 	if err != nil {
-		return fmt.Errorf("Unable to update secret: %s", err.Error())
+		return util.StepError(ctx, "updating secret "+pathToSecret, fmt.Errorf("Unable to update secret: %s", err.Error()))
 	}
 
 	return resourceDcosSecuritySecretRead(d, meta)
@@ -141,7 +144,8 @@ func resourceDcosSecuritySecretUpdate(d *schema.ResourceData, meta interface{})
 
 func resourceDcosSecuritySecretDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*dcos.APIClient)
-	ctx := context.TODO()
+	ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+	defer cancel()
 
 	pathToSecret := d.Get("path").(string)
 	store := d.Get("store").(string)
@@ -154,7 +158,7 @@ func resourceDcosSecuritySecretDelete(d *schema.ResourceData, meta interface{})
 	}
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete secret: %s", err.Error())
+		return util.StepError(ctx, "deleting secret "+pathToSecret, fmt.Errorf("Unable to delete secret: %s", err.Error()))
 	}
 
 	d.SetId("")
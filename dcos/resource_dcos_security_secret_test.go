@@ -0,0 +1,54 @@
+package dcos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/testserver"
+)
+
+/** Test creating and updating a secret against the test server */
+func TestDcosSecuritySecret_testserver(t *testing.T) {
+	server := testserver.New()
+	defer server.Close()
+
+	config := func(value string) string {
+		return server.ProviderConfig() + fmt.Sprintf(`
+resource "dcos_security_secret" "test" {
+  path  = "test/secret"
+  value = %q
+}
+`, value)
+	}
+
+	checkValue := func(expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if value, ok := server.Secret("default", "test/secret"); !ok || value != expected {
+				return fmt.Errorf("Secret has value %q, expected %q", value, expected)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testUnitProviders(),
+		CheckDestroy: func(*terraform.State) error {
+			if _, ok := server.Secret("default", "test/secret"); ok {
+				return fmt.Errorf("Secret still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("first"),
+				Check:  checkValue("first"),
+			},
+			{
+				Config: config("second"),
+				Check:  checkValue("second"),
+			},
+		},
+	})
+}
@@ -2,6 +2,7 @@ package dcos
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/dcos/client-go/dcos"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 func resourceDcosServiceHttpRequest() *schema.Resource {
@@ -81,7 +83,7 @@ func resourceDcosServiceHttpRequest() *schema.Resource {
 	}
 }
 
-func placeRequest(d *schema.ResourceData, meta interface{}) error {
+func placeRequest(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	apiClient := meta.(*dcos.APIClient)
 	config := apiClient.CurrentDCOSConfig()
 
@@ -93,14 +95,14 @@ func placeRequest(d *schema.ResourceData, meta interface{}) error {
 
 	url := fmt.Sprintf("%s/service/%s%s", config.URL(), cfgServiceName, cfgPath)
 
-	log.Printf("[TRACE] Posting body: %s", cfgBody)
+	log.Printf("[TRACE] Posting body: %s", util.RedactBody(cfgPath, cfgBody))
 
-	request, err := http.NewRequest(cfgMethod, url, bytes.NewReader(cfgBody))
+	request, err := http.NewRequestWithContext(ctx, cfgMethod, url, bytes.NewReader(cfgBody))
 	if err != nil {
 		return fmt.Errorf("Unable to prepare request: %s", err.Error())
 	}
 
-	request.Header.Add("Authorization", config.ACSToken())
+	request.Header.Add("Authorization", fmt.Sprintf("token=%s", util.ACSToken(apiClient)))
 	for _, hdr := range cfgHeaders {
 		if recMap, ok := hdr.(map[string]interface{}); ok {
 			if iName, ok := recMap["name"]; ok {
@@ -117,7 +119,7 @@ func placeRequest(d *schema.ResourceData, meta interface{}) error {
 
 	response, err := apiClient.HTTPClient().Do(request)
 	if err != nil {
-		return fmt.Errorf("Unable to place request: %s", err.Error())
+		return util.StepError(ctx, "placing request to "+url, fmt.Errorf("Unable to place request: %s", err.Error()))
 	}
 	defer response.Body.Close()
 
@@ -133,7 +135,10 @@ func resourceDcosServiceHttpRequestCreate(d *schema.ResourceData, meta interface
 	runTrigger := d.Get("run_on").(string)
 
 	if runTrigger == "create" {
-		err := placeRequest(d, meta)
+		ctx, cancel := util.TimeoutContext(d, schema.TimeoutCreate)
+		defer cancel()
+
+		err := placeRequest(ctx, d, meta)
 		if err != nil {
 			return err
 		}
@@ -152,7 +157,10 @@ func resourceDcosServiceHttpRequestDelete(d *schema.ResourceData, meta interface
 	runTrigger := d.Get("run_on").(string)
 
 	if runTrigger == "delete" {
-		err := placeRequest(d, meta)
+		ctx, cancel := util.TimeoutContext(d, schema.TimeoutDelete)
+		defer cancel()
+
+		err := placeRequest(ctx, d, meta)
 		if err != nil {
 			return err
 		}
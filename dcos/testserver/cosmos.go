@@ -0,0 +1,371 @@
+package testserver
+
+import (
+	"net/http"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+// universePath is where the package catalog is served in the repository
+// format, so it can also be used as a `repo_url`
+const universePath = "/package-repo/repo.json"
+
+// DefaultRepositoryURL is the URL of the repository configured on a new server
+const DefaultRepositoryURL = "https://universe.mesosphere.com/repo"
+
+type cosmosInstallation struct {
+	pkg     dcos.CosmosPackage
+	options map[string]interface{}
+}
+
+type cosmosState struct {
+	repos     []dcos.CosmosPackageRepo
+	catalog   []dcos.CosmosPackage
+	installed map[string]*cosmosInstallation
+}
+
+func newCosmosState() cosmosState {
+	return cosmosState{
+		repos: []dcos.CosmosPackageRepo{
+			{Name: "Universe", Uri: DefaultRepositoryURL},
+		},
+		installed: make(map[string]*cosmosInstallation),
+	}
+}
+
+// RepositoryURL returns the URL of the package catalog of the server in the
+// Universe repository format
+func (s *Server) RepositoryURL() string {
+	return s.URL + universePath
+}
+
+// AddPackage adds a package version to the catalog of the server
+func (s *Server) AddPackage(pkg dcos.CosmosPackage) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if pkg.PackagingVersion == "" {
+		pkg.PackagingVersion = "4.0"
+	}
+	s.cosmos.catalog = append(s.cosmos.catalog, pkg)
+}
+
+// Repositories returns the package repositories configured on the server
+func (s *Server) Repositories() []dcos.CosmosPackageRepo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]dcos.CosmosPackageRepo(nil), s.cosmos.repos...)
+}
+
+// InstalledPackage returns the package installed as the given app ID and the
+// options it was installed with
+func (s *Server) InstalledPackage(appID string) (dcos.CosmosPackage, map[string]interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	installation, ok := s.cosmos.installed[marathonID(appID)]
+	if !ok {
+		return dcos.CosmosPackage{}, nil, false
+	}
+	return installation.pkg, copyJSON(installation.options), true
+}
+
+func writeCosmosError(w http.ResponseWriter, status int, errorType, message string) {
+	writeJSON(w, status, dcos.CosmosError{
+		Type:    errorType,
+		Message: message,
+	})
+}
+
+// findPackage returns the given, or the latest version of a package. Must be
+// called with the lock held.
+func (s *Server) findPackage(name, version string) (dcos.CosmosPackage, bool) {
+	var found dcos.CosmosPackage
+	ok := false
+	for _, pkg := range s.cosmos.catalog {
+		if pkg.Name != name {
+			continue
+		}
+		if version == "" || pkg.Version == version {
+			found, ok = pkg, true
+		}
+	}
+	return found, ok
+}
+
+// schemaDefaults collects the default values of a JSON schema
+func schemaDefaults(schema map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{})
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, v := range properties {
+		property, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, ok := property["default"]; ok {
+			ret[name] = value
+		} else if property["type"] == "object" {
+			if nested := schemaDefaults(property); len(nested) > 0 {
+				ret[name] = nested
+			}
+		}
+	}
+
+	return ret
+}
+
+// mergeOptions deeply merges the options over the given defaults
+func mergeOptions(defaults, options map[string]interface{}) map[string]interface{} {
+	ret := copyJSON(defaults)
+	if ret == nil {
+		ret = make(map[string]interface{})
+	}
+
+	for k, v := range options {
+		nested, isMap := v.(map[string]interface{})
+		existing, wasMap := ret[k].(map[string]interface{})
+		if isMap && wasMap {
+			ret[k] = mergeOptions(existing, nested)
+		} else {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func (s *Server) serveUniverse(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"packages": s.cosmos.catalog,
+	})
+}
+
+func (s *Server) serveCosmos(w http.ResponseWriter, r *http.Request, path string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch path {
+	case "/package/describe":
+		var req dcos.CosmosPackageDescribeV1Request
+		if err := readJSON(r, &req); err != nil {
+			writeCosmosError(w, http.StatusBadRequest, "JsonParsingError", err.Error())
+			return
+		}
+		pkg, ok := s.findPackage(req.PackageName, req.PackageVersion)
+		if !ok {
+			writeCosmosError(w, http.StatusNotFound, "PackageNotFound", "Package ["+req.PackageName+"] not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, dcos.CosmosPackageDescribeV3Response{Package: pkg})
+
+	case "/package/install":
+		var req dcos.CosmosPackageInstallV1Request
+		if err := readJSON(r, &req); err != nil {
+			writeCosmosError(w, http.StatusBadRequest, "JsonParsingError", err.Error())
+			return
+		}
+		pkg, ok := s.findPackage(req.PackageName, req.PackageVersion)
+		if !ok {
+			writeCosmosError(w, http.StatusBadRequest, "PackageNotFound", "Package ["+req.PackageName+"] not found")
+			return
+		}
+
+		appID := req.AppId
+		if appID == "" {
+			appID = pkg.Name
+		}
+		appID = marathonID(appID)
+		if _, exists := s.cosmos.installed[appID]; exists {
+			writeCosmosError(w, http.StatusConflict, "PackageAlreadyInstalled", "Package is already installed")
+			return
+		}
+
+		s.cosmos.installed[appID] = &cosmosInstallation{pkg: pkg, options: req.Options}
+		s.deployPackage(appID, pkg)
+
+		writeJSON(w, http.StatusOK, dcos.CosmosPackageInstallV1Response{
+			AppId:            appID,
+			PackageName:      pkg.Name,
+			PackageVersion:   pkg.Version,
+			PostInstallNotes: pkg.PostInstallNotes,
+		})
+
+	case "/package/list":
+		var req dcos.CosmosPackageListV1Request
+		if err := readJSON(r, &req); err != nil {
+			writeCosmosError(w, http.StatusBadRequest, "JsonParsingError", err.Error())
+			return
+		}
+		resp := dcos.CosmosPackageListV1Response{Packages: []dcos.CosmosPackageListV1Package{}}
+		for appID, installation := range s.cosmos.installed {
+			if req.AppId != "" && marathonID(req.AppId) != appID {
+				continue
+			}
+			if req.PackageName != "" && req.PackageName != installation.pkg.Name {
+				continue
+			}
+			resp.Packages = append(resp.Packages, dcos.CosmosPackageListV1Package{
+				AppId: appID,
+				PackageInformation: dcos.CosmosPackageListV1PackageInformation{
+					AppId:             appID,
+					PackageDefinition: installation.pkg,
+				},
+			})
+		}
+		writeJSON(w, http.StatusOK, resp)
+
+	case "/package/uninstall":
+		var req dcos.CosmosPackageUninstallV1Request
+		if err := readJSON(r, &req); err != nil {
+			writeCosmosError(w, http.StatusBadRequest, "JsonParsingError", err.Error())
+			return
+		}
+		resp := dcos.CosmosPackageUninstallV1Response{Results: []dcos.CosmosPackageUninstallV1ResponseResults{}}
+		for appID, installation := range s.cosmos.installed {
+			if installation.pkg.Name != req.PackageName {
+				continue
+			}
+			if !req.All && marathonID(req.AppId) != appID {
+				continue
+			}
+			delete(s.cosmos.installed, appID)
+			s.marathons[DefaultMarathonPath].removeApp(appID)
+			resp.Results = append(resp.Results, dcos.CosmosPackageUninstallV1ResponseResults{
+				AppId:              appID,
+				PackageName:        installation.pkg.Name,
+				PackageVersion:     installation.pkg.Version,
+				PostUninstallNotes: installation.pkg.PostUninstallNotes,
+			})
+		}
+		if len(resp.Results) == 0 {
+			writeCosmosError(w, http.StatusNotFound, "PackageNotInstalled", "Package ["+req.PackageName+"] with id ["+req.AppId+"] is not installed")
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+
+	case "/package/repository/list":
+		writeJSON(w, http.StatusOK, dcos.CosmosPackageListRepoV1Response{Repositories: s.cosmos.repos})
+
+	case "/package/repository/add":
+		var req dcos.CosmosPackageAddRepoV1Request
+		if err := readJSON(r, &req); err != nil {
+			writeCosmosError(w, http.StatusBadRequest, "JsonParsingError", err.Error())
+			return
+		}
+		for _, repo := range s.cosmos.repos {
+			if repo.Name == req.Name || repo.Uri == req.Uri {
+				writeCosmosError(w, http.StatusConflict, "RepositoryAlreadyPresent", "Repository ["+req.Name+"] is already present")
+				return
+			}
+		}
+
+		index := len(s.cosmos.repos)
+		if req.Index != nil && int(*req.Index) >= 0 && int(*req.Index) < index {
+			index = int(*req.Index)
+		}
+		repos := append([]dcos.CosmosPackageRepo{}, s.cosmos.repos[:index]...)
+		repos = append(repos, dcos.CosmosPackageRepo{Name: req.Name, Uri: req.Uri})
+		s.cosmos.repos = append(repos, s.cosmos.repos[index:]...)
+		writeJSON(w, http.StatusOK, dcos.CosmosPackageAddRepoV1Response{Repositories: s.cosmos.repos})
+
+	case "/package/repository/delete":
+		var req dcos.CosmosPackageDeleteRepoV1Request
+		if err := readJSON(r, &req); err != nil {
+			writeCosmosError(w, http.StatusBadRequest, "JsonParsingError", err.Error())
+			return
+		}
+		for i, repo := range s.cosmos.repos {
+			if (req.Name != "" && repo.Name == req.Name) || (req.Uri != "" && repo.Uri == req.Uri) {
+				s.cosmos.repos = append(s.cosmos.repos[:i:i], s.cosmos.repos[i+1:]...)
+				writeJSON(w, http.StatusOK, dcos.CosmosPackageDeleteRepoV1Response{Repositories: s.cosmos.repos})
+				return
+			}
+		}
+		writeCosmosError(w, http.StatusNotFound, "RepositoryNotPresent", "Repository ["+req.Name+"] is not present")
+
+	case "/cosmos/service/describe":
+		var req dcos.CosmosServiceDescribeV1Request
+		if err := readJSON(r, &req); err != nil {
+			writeCosmosError(w, http.StatusBadRequest, "JsonParsingError", err.Error())
+			return
+		}
+		installation, ok := s.cosmos.installed[marathonID(req.AppId)]
+		if !ok {
+			// Cosmos reports unknown services as bad requests
+			writeCosmosError(w, http.StatusBadRequest, "MarathonAppNotFound", "Unable to locate service with marathon appId: ["+req.AppId+"]")
+			return
+		}
+		writeJSON(w, http.StatusOK, dcos.CosmosServiceDescribeV1Response{
+			DowngradesTo:        []string{},
+			UpgradesTo:          []string{},
+			Package:             installation.pkg,
+			ResolvedOptions:     mergeOptions(schemaDefaults(installation.pkg.Config), installation.options),
+			UserProvidedOptions: installation.options,
+		})
+
+	case "/cosmos/service/update":
+		var req dcos.CosmosServiceUpdateV1Request
+		if err := readJSON(r, &req); err != nil {
+			writeCosmosError(w, http.StatusBadRequest, "JsonParsingError", err.Error())
+			return
+		}
+		appID := marathonID(req.AppId)
+		installation, ok := s.cosmos.installed[appID]
+		if !ok {
+			writeCosmosError(w, http.StatusBadRequest, "MarathonAppNotFound", "Unable to locate service with marathon appId: ["+req.AppId+"]")
+			return
+		}
+		if req.PackageName != "" && req.PackageName != installation.pkg.Name {
+			writeCosmosError(w, http.StatusBadRequest, "ServiceUpdateError", "Cannot change the package of a service")
+			return
+		}
+		if req.PackageVersion != "" && req.PackageVersion != installation.pkg.Version {
+			pkg, ok := s.findPackage(installation.pkg.Name, req.PackageVersion)
+			if !ok {
+				writeCosmosError(w, http.StatusBadRequest, "VersionNotFound", "Version ["+req.PackageVersion+"] of package ["+installation.pkg.Name+"] not found")
+				return
+			}
+			installation.pkg = pkg
+		}
+		if req.Replace {
+			installation.options = req.Options
+		} else {
+			installation.options = mergeOptions(installation.options, req.Options)
+		}
+
+		deployment := s.deployPackage(appID, installation.pkg)
+		writeJSON(w, http.StatusOK, dcos.CosmosServiceUpdateV1Response{
+			MarathonDeploymentId: deployment.id,
+			Package:              installation.pkg,
+			ResolvedOptions:      mergeOptions(schemaDefaults(installation.pkg.Config), installation.options),
+		})
+
+	default:
+		writeCosmosError(w, http.StatusNotFound, "NotFound", "No fake Cosmos endpoint for "+path)
+	}
+}
+
+// deployPackage (re-)deploys the root Marathon app of an installed package.
+// Must be called with the lock held.
+func (s *Server) deployPackage(appID string, pkg dcos.CosmosPackage) *marathonDeployment {
+	marathon := s.marathons[DefaultMarathonPath]
+	deployment := marathon.deploy([]string{appID}, nil)
+	marathon.storeApp(appID, map[string]interface{}{
+		"instances": 1,
+		"cmd":       pkg.Name,
+		"labels": map[string]interface{}{
+			"DCOS_PACKAGE_NAME":    pkg.Name,
+			"DCOS_PACKAGE_VERSION": pkg.Version,
+		},
+	}, deployment.version)
+	return deployment
+}
@@ -0,0 +1,100 @@
+package testserver
+
+import (
+	"net/http"
+)
+
+// EdgeLBPool returns the EdgeLB pool with the given name
+func (s *Server) EdgeLBPool(name string) (map[string]interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	pool, ok := s.edgelb[name]
+	return copyJSON(pool), ok
+}
+
+func writeEdgeLBError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"code":    status,
+		"message": message,
+	})
+}
+
+func (s *Server) serveEdgeLB(w http.ResponseWriter, r *http.Request, path string) {
+	if path == "/ping" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("pong"))
+		return
+	}
+
+	parts := splitPath(path)
+	if len(parts) < 2 || parts[0] != "v2" || parts[1] != "pools" || len(parts) > 3 {
+		writeEdgeLBError(w, http.StatusNotFound, "No such endpoint "+path)
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(parts) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			pools := []map[string]interface{}{}
+			for _, pool := range s.edgelb {
+				pools = append(pools, pool)
+			}
+			writeJSON(w, http.StatusOK, pools)
+
+		case http.MethodPost:
+			var pool map[string]interface{}
+			if err := readJSON(r, &pool); err != nil {
+				writeEdgeLBError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			name, _ := pool["name"].(string)
+			if name == "" {
+				writeEdgeLBError(w, http.StatusBadRequest, "Missing pool name")
+				return
+			}
+			if _, exists := s.edgelb[name]; exists {
+				writeEdgeLBError(w, http.StatusConflict, "Pool "+name+" already exists")
+				return
+			}
+			s.edgelb[name] = pool
+			writeJSON(w, http.StatusOK, pool)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	name := parts[2]
+	pool, exists := s.edgelb[name]
+	if !exists {
+		writeEdgeLBError(w, http.StatusNotFound, "Pool "+name+" not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, pool)
+
+	case http.MethodPut:
+		var update map[string]interface{}
+		if err := readJSON(r, &update); err != nil {
+			writeEdgeLBError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		update["name"] = name
+		s.edgelb[name] = update
+		writeJSON(w, http.StatusOK, update)
+
+	case http.MethodDelete:
+		delete(s.edgelb, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
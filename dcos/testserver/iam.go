@@ -0,0 +1,535 @@
+package testserver
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+type iamUser struct {
+	dcos.IamUser
+	password string
+}
+
+type iamState struct {
+	users     map[string]*iamUser
+	groups    map[string]dcos.IamGroup
+	members   map[string]map[string]bool
+	acls      map[string]map[string]map[string]bool
+	providers map[string]map[string]interface{}
+}
+
+func newIAMState() iamState {
+	return iamState{
+		users: map[string]*iamUser{
+			DefaultUser: {
+				IamUser: dcos.IamUser{
+					Uid:          DefaultUser,
+					Description:  "Bootstrap superuser",
+					ProviderType: "internal",
+				},
+				password: DefaultPassword,
+			},
+		},
+		groups:    make(map[string]dcos.IamGroup),
+		members:   make(map[string]map[string]bool),
+		acls:      make(map[string]map[string]map[string]bool),
+		providers: make(map[string]map[string]interface{}),
+	}
+}
+
+// User returns the IAM user or service account with the given ID
+func (s *Server) User(uid string) (dcos.IamUser, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if user, ok := s.iam.users[uid]; ok {
+		return user.IamUser, true
+	}
+	return dcos.IamUser{}, false
+}
+
+// Group returns the IAM group with the given ID
+func (s *Server) Group(gid string) (dcos.IamGroup, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	group, ok := s.iam.groups[gid]
+	return group, ok
+}
+
+// IsPermitted checks if the user was granted the action on the resource
+func (s *Server) IsPermitted(uid, rid, action string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.iam.acls[rid][uid][action]
+}
+
+func writeIAMError(w http.ResponseWriter, status int, code string, description string) {
+	writeJSON(w, status, map[string]string{
+		"title":       http.StatusText(status),
+		"description": description,
+		"code":        code,
+	})
+}
+
+func (s *Server) serveLogin(w http.ResponseWriter, r *http.Request) {
+	var login dcos.IamLoginObject
+	if err := readJSON(r, &login); err != nil {
+		writeIAMError(w, http.StatusBadRequest, "ERR_INVALID_DATA", err.Error())
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Service accounts log in with a token signed by their private key,
+	// which is trusted as long as the account has a public key
+	user, ok := s.iam.users[login.Uid]
+	if !ok || (login.Password == "" || login.Password != user.password) && (login.Token == "" || user.PublicKey == "") {
+		writeIAMError(w, http.StatusUnauthorized, "ERR_INVALID_CREDENTIALS", "The credentials provided are invalid")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dcos.IamAuthToken{Token: s.issueToken(login.Uid)})
+}
+
+func (s *Server) serveIAM(w http.ResponseWriter, r *http.Request, path string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch {
+	case strings.HasPrefix(path, "/users/"):
+		s.serveIAMUsers(w, r, splitPath(strings.TrimPrefix(path, "/users/")))
+	case strings.HasPrefix(path, "/groups/"):
+		s.serveIAMGroups(w, r, splitPath(strings.TrimPrefix(path, "/groups/")))
+	case strings.HasPrefix(path, "/acls/"):
+		s.serveIAMACLs(w, r, strings.TrimPrefix(path, "/acls/"))
+	case strings.HasPrefix(path, "/auth/oidc/providers/"), strings.HasPrefix(path, "/auth/saml/providers/"):
+		s.serveIAMProviders(w, r, path)
+	default:
+		writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", path)
+	}
+}
+
+func (s *Server) serveIAMUsers(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", "Missing user ID")
+		return
+	}
+
+	uid := parts[0]
+	user, exists := s.iam.users[uid]
+
+	if len(parts) == 2 && parts[1] == "permissions" && r.Method == http.MethodGet {
+		if !exists {
+			writeIAMError(w, http.StatusBadRequest, "ERR_UNKNOWN_USER_ID", "User "+uid+" does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.userPermissions(uid))
+		return
+	}
+	if len(parts) != 1 {
+		writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", strings.Join(parts, "/"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_USER_ID", "User "+uid+" does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, user.IamUser)
+
+	case http.MethodPut:
+		var create dcos.IamUserCreate
+		if err := readJSON(r, &create); err != nil {
+			writeIAMError(w, http.StatusBadRequest, "ERR_INVALID_DATA", err.Error())
+			return
+		}
+		if exists {
+			writeIAMError(w, http.StatusConflict, "ERR_USER_EXISTS", "User "+uid+" already exists")
+			return
+		}
+
+		providerType := create.ProviderType
+		if providerType == "" {
+			providerType = "internal"
+		}
+		s.iam.users[uid] = &iamUser{
+			IamUser: dcos.IamUser{
+				Uid:          uid,
+				Url:          "/acs/api/v1/users/" + uid,
+				Description:  create.Description,
+				IsRemote:     providerType != "internal",
+				IsService:    create.PublicKey != "",
+				PublicKey:    create.PublicKey,
+				ProviderType: providerType,
+				ProviderId:   create.ProviderId,
+			},
+			password: create.Password,
+		}
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodPatch:
+		var update dcos.IamUserUpdate
+		if err := readJSON(r, &update); err != nil {
+			writeIAMError(w, http.StatusBadRequest, "ERR_INVALID_DATA", err.Error())
+			return
+		}
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_USER_ID", "User "+uid+" does not exist")
+			return
+		}
+		if update.Description != "" {
+			user.Description = update.Description
+		}
+		if update.Password != "" {
+			user.password = update.Password
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_USER_ID", "User "+uid+" does not exist")
+			return
+		}
+		delete(s.iam.users, uid)
+		for _, members := range s.iam.members {
+			delete(members, uid)
+		}
+		for _, users := range s.iam.acls {
+			delete(users, uid)
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// userPermissions lists the direct permissions of a user
+func (s *Server) userPermissions(uid string) dcos.IamUserPermissions {
+	var rids []string
+	for rid := range s.iam.acls {
+		rids = append(rids, rid)
+	}
+	sort.Strings(rids)
+
+	permissions := dcos.IamUserPermissions{Direct: []dcos.IamUserPermissionsDirect{}}
+	for _, rid := range rids {
+		actions := s.iam.acls[rid][uid]
+		if len(actions) == 0 {
+			continue
+		}
+
+		direct := dcos.IamUserPermissionsDirect{
+			Rid:    rid,
+			Aclurl: "/acs/api/v1/acls/" + url.PathEscape(rid),
+		}
+		for action := range actions {
+			direct.Actions = append(direct.Actions, dcos.IamAction{Name: action})
+		}
+		sort.Slice(direct.Actions, func(i, j int) bool { return direct.Actions[i].Name < direct.Actions[j].Name })
+		permissions.Direct = append(permissions.Direct, direct)
+	}
+
+	return permissions
+}
+
+func (s *Server) serveIAMGroups(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", "Missing group ID")
+		return
+	}
+
+	gid := parts[0]
+	group, exists := s.iam.groups[gid]
+
+	if len(parts) > 1 && parts[1] == "users" {
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_GROUP_ID", "Group "+gid+" does not exist")
+			return
+		}
+		s.serveIAMGroupUsers(w, r, gid, parts[2:])
+		return
+	}
+	if len(parts) != 1 {
+		writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", strings.Join(parts, "/"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_GROUP_ID", "Group "+gid+" does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, group)
+
+	case http.MethodPut:
+		var create dcos.IamGroupCreate
+		if err := readJSON(r, &create); err != nil {
+			writeIAMError(w, http.StatusBadRequest, "ERR_INVALID_DATA", err.Error())
+			return
+		}
+		if exists {
+			writeIAMError(w, http.StatusConflict, "ERR_GROUP_EXISTS", "Group "+gid+" already exists")
+			return
+		}
+		s.iam.groups[gid] = dcos.IamGroup{
+			Gid:          gid,
+			Url:          "/acs/api/v1/groups/" + gid,
+			Description:  create.Description,
+			ProviderType: "internal",
+		}
+		s.iam.members[gid] = make(map[string]bool)
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodPatch:
+		var update dcos.IamGroupUpdate
+		if err := readJSON(r, &update); err != nil {
+			writeIAMError(w, http.StatusBadRequest, "ERR_INVALID_DATA", err.Error())
+			return
+		}
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_GROUP_ID", "Group "+gid+" does not exist")
+			return
+		}
+		group.Description = update.Description
+		s.iam.groups[gid] = group
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_GROUP_ID", "Group "+gid+" does not exist")
+			return
+		}
+		delete(s.iam.groups, gid)
+		delete(s.iam.members, gid)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveIAMGroupUsers(w http.ResponseWriter, r *http.Request, gid string, parts []string) {
+	members := s.iam.members[gid]
+
+	if len(parts) == 0 && r.Method == http.MethodGet {
+		// Like IAM, list either users or service accounts
+		wantServices := r.URL.Query().Get("type") == "service"
+
+		var uids []string
+		for uid := range members {
+			uids = append(uids, uid)
+		}
+		sort.Strings(uids)
+
+		users := dcos.IamGroupUsers{Array: []dcos.IamGroupUsersArray{}}
+		for _, uid := range uids {
+			user := s.iam.users[uid]
+			if user != nil && user.IsService == wantServices {
+				users.Array = append(users.Array, dcos.IamGroupUsersArray{
+					Membershipurl: "/acs/api/v1/groups/" + gid + "/users/" + uid,
+					User:          user.IamUser,
+				})
+			}
+		}
+		writeJSON(w, http.StatusOK, users)
+		return
+	}
+	if len(parts) != 1 {
+		writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", strings.Join(parts, "/"))
+		return
+	}
+
+	uid := parts[0]
+	switch r.Method {
+	case http.MethodPut:
+		if _, ok := s.iam.users[uid]; !ok {
+			writeIAMError(w, http.StatusBadRequest, "ERR_UNKNOWN_USER_ID", "User "+uid+" does not exist")
+			return
+		}
+		if members[uid] {
+			writeIAMError(w, http.StatusConflict, "ERR_MEMBERSHIP_EXISTS", "User "+uid+" is already member of "+gid)
+			return
+		}
+		members[uid] = true
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !members[uid] {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_MEMBERSHIP", "User "+uid+" is not member of "+gid)
+			return
+		}
+		delete(members, uid)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// serveIAMACLs handles `{rid}` and `{rid}/users/{uid}/{action}`. The resource
+// ID might contain slashes, either raw or escaped.
+func (s *Server) serveIAMACLs(w http.ResponseWriter, r *http.Request, path string) {
+	rid := path
+	var parts []string
+	if idx := strings.LastIndex(path, "/users/"); idx >= 0 {
+		rid = path[:idx]
+		parts = splitPath(path[idx+len("/users/"):])
+	}
+	if unescaped, err := url.PathUnescape(rid); err == nil {
+		rid = unescaped
+	}
+
+	users, exists := s.iam.acls[rid]
+
+	if parts == nil {
+		switch r.Method {
+		case http.MethodPut:
+			if exists {
+				writeIAMError(w, http.StatusConflict, "ERR_ACL_EXISTS", "ACL for resource "+rid+" already exists")
+				return
+			}
+			s.iam.acls[rid] = make(map[string]map[string]bool)
+			w.WriteHeader(http.StatusCreated)
+
+		case http.MethodDelete:
+			if !exists {
+				writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_RESOURCE_ID", "ACL for resource "+rid+" does not exist")
+				return
+			}
+			delete(s.iam.acls, rid)
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if len(parts) != 2 {
+		writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", path)
+		return
+	}
+	if !exists {
+		writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_RESOURCE_ID", "ACL for resource "+rid+" does not exist")
+		return
+	}
+
+	uid, action := parts[0], parts[1]
+	switch r.Method {
+	case http.MethodPut:
+		if _, ok := s.iam.users[uid]; !ok {
+			writeIAMError(w, http.StatusBadRequest, "ERR_UNKNOWN_USER_ID", "User "+uid+" does not exist")
+			return
+		}
+		if users[uid] == nil {
+			users[uid] = make(map[string]bool)
+		}
+		if users[uid][action] {
+			writeIAMError(w, http.StatusConflict, "ERR_PERMISSION_EXISTS", "Permission already exists")
+			return
+		}
+		users[uid][action] = true
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !users[uid][action] {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_PERMISSION", "Permission does not exist")
+			return
+		}
+		delete(users[uid], action)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// serveIAMProviders keeps the OIDC and SAML provider configurations as-is
+func (s *Server) serveIAMProviders(w http.ResponseWriter, r *http.Request, path string) {
+	key := path
+	parts := splitPath(path)
+	if len(parts) == 6 {
+		key = "/" + strings.Join(parts[:5], "/")
+	}
+	config, exists := s.iam.providers[key]
+
+	if len(parts) == 6 && r.Method == http.MethodGet {
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_PROVIDER_ID", "Provider does not exist")
+			return
+		}
+
+		switch parts[5] {
+		case "acs-callback-url":
+			writeJSON(w, http.StatusOK, dcos.IamsamlacsCallbackUrlObject{
+				AcsCallbackUrl: s.URL + "/acs/api/v1" + key + "/acs-callback",
+			})
+		case "sp-metadata":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(`<?xml version="1.0"?><md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="` + s.URL + `"/>`))
+		default:
+			writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", path)
+		}
+		return
+	}
+	if len(parts) != 5 {
+		writeIAMError(w, http.StatusNotFound, "ERR_NOT_FOUND", path)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_PROVIDER_ID", "Provider does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, config)
+
+	case http.MethodPut, http.MethodPatch:
+		var update map[string]interface{}
+		if err := readJSON(r, &update); err != nil {
+			writeIAMError(w, http.StatusBadRequest, "ERR_INVALID_DATA", err.Error())
+			return
+		}
+		if r.Method == http.MethodPut {
+			if exists {
+				writeIAMError(w, http.StatusConflict, "ERR_PROVIDER_EXISTS", "Provider already exists")
+				return
+			}
+			s.iam.providers[key] = update
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_PROVIDER_ID", "Provider does not exist")
+			return
+		}
+		for k, v := range update {
+			config[k] = v
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		if !exists {
+			writeIAMError(w, http.StatusNotFound, "ERR_UNKNOWN_PROVIDER_ID", "Provider does not exist")
+			return
+		}
+		delete(s.iam.providers, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,1547 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type marathonDeployment struct {
+	id      string
+	version string
+	apps    []string
+	pods    []string
+	failure string
+	// The instances of pods kept by the deployment, as only scaling changed
+	kept map[string][]interface{}
+}
+
+type marathonAppStatus struct {
+	staged  int
+	running int
+	healthy int
+	failure map[string]interface{}
+}
+
+type marathonState struct {
+	server      *Server
+	apps        map[string]map[string]interface{}
+	appVersions map[string][]map[string]interface{}
+	appStatus   map[string]*marathonAppStatus
+	pods        map[string]map[string]interface{}
+	podStatus   map[string]map[string]interface{}
+	groups      map[string]map[string]interface{}
+	deployments map[string]*marathonDeployment
+	failures    map[string]string
+	held        map[string]bool
+	declines    map[string]map[string]int
+	listeners   map[chan string]bool
+}
+
+func newMarathonState(s *Server) *marathonState {
+	return &marathonState{
+		server:      s,
+		apps:        make(map[string]map[string]interface{}),
+		appVersions: make(map[string][]map[string]interface{}),
+		appStatus:   make(map[string]*marathonAppStatus),
+		pods:        make(map[string]map[string]interface{}),
+		podStatus:   make(map[string]map[string]interface{}),
+		groups:      make(map[string]map[string]interface{}),
+		deployments: make(map[string]*marathonDeployment),
+		failures:    make(map[string]string),
+		held:        make(map[string]bool),
+		declines:    make(map[string]map[string]int),
+		listeners:   make(map[chan string]bool),
+	}
+}
+
+// marathonID normalizes an app or pod ID to its absolute form
+func marathonID(id string) string {
+	return "/" + strings.Trim(id, "/")
+}
+
+// marathonAt returns the Marathon instance served on the given path
+func (s *Server) marathonAt(path string) *marathonState {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path = "/" + strings.Trim(path, "/")
+	if m, ok := s.marathons[path]; ok {
+		return m
+	}
+	return s.marathons[DefaultMarathonPath]
+}
+
+// App returns the definition of the app on the Marathon served on the given
+// path, or on the root Marathon if the path is empty
+func (s *Server) App(marathonPath, id string) (map[string]interface{}, bool) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	app, ok := m.apps[marathonID(id)]
+	return copyJSON(app), ok
+}
+
+// Pod returns the definition of the pod on the Marathon served on the given
+// path, or on the root Marathon if the path is empty
+func (s *Server) Pod(marathonPath, id string) (map[string]interface{}, bool) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	pod, ok := m.pods[marathonID(id)]
+	return copyJSON(pod), ok
+}
+
+// RemovePod removes the pod from the Marathon served on the given path, or
+// from the root Marathon if the path is empty, like it was removed out of band
+func (s *Server) RemovePod(marathonPath, id string) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id = marathonID(id)
+	m.deploy(nil, []string{id})
+	delete(m.pods, id)
+	delete(m.podStatus, id)
+}
+
+// CreateApp deploys the app on the Marathon served on the given path, or on
+// the root Marathon if the path is empty, like it was created out of band
+func (s *Server) CreateApp(marathonPath string, app map[string]interface{}) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id := marathonID(app["id"].(string))
+	deployment := m.deploy([]string{id}, nil)
+	m.storeApp(id, copyJSON(app), deployment.version)
+}
+
+// PodInstances returns the IDs and states of the instances of the pod on the
+// Marathon served on the given path, or on the root Marathon if the path is
+// empty
+func (s *Server) PodInstances(marathonPath, id string) map[string]string {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ret := map[string]string{}
+	instances, _ := m.podStatus[marathonID(id)]["instances"].([]interface{})
+	for _, instance := range instances {
+		instance := instance.(map[string]interface{})
+		ret[instance["id"].(string)] = instance["status"].(string)
+	}
+	return ret
+}
+
+// MarathonGroup returns the definition of the group created explicitly on the Marathon
+// served on the given path, or on the root Marathon if the path is empty
+func (s *Server) MarathonGroup(marathonPath, id string) (map[string]interface{}, bool) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	group, ok := m.groups[marathonID(id)]
+	return copyJSON(group), ok
+}
+
+// Deployments returns the IDs of the deployments in progress on the Marathon
+// served on the given path, or on the root Marathon if the path is empty
+func (s *Server) Deployments(marathonPath string) []string {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ids := []string{}
+	for id := range m.deployments {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// FailDeployments makes all following deployments of the app or pod fail
+// with the given task failure message. An empty message lets them succeed again.
+// Restoring a previous version is not affected.
+func (s *Server) FailDeployments(marathonPath, id, message string) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if message == "" {
+		delete(m.failures, marathonID(id))
+	} else {
+		m.failures[marathonID(id)] = message
+	}
+}
+
+func writeMarathonError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+func (m *marathonState) serveHTTP(w http.ResponseWriter, r *http.Request, path string) {
+	switch {
+	case path == "/ping":
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("pong"))
+		return
+	case path == "/v2/events":
+		m.serveEvents(w, r)
+		return
+	}
+
+	m.server.lock.Lock()
+	defer m.server.lock.Unlock()
+
+	switch {
+	case path == "/v2/info":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"name":    "marathon",
+			"version": "1.8.0",
+			"leader":  strings.TrimPrefix(m.server.URL, "http://"),
+		})
+	case path == "/v2/apps" || strings.HasPrefix(path, "/v2/apps/"):
+		m.serveApps(w, r, strings.TrimPrefix(path, "/v2/apps"))
+	case path == "/v2/pods" || strings.HasPrefix(path, "/v2/pods/"):
+		m.servePods(w, r, strings.TrimPrefix(path, "/v2/pods"))
+	case path == "/v2/groups" || strings.HasPrefix(path, "/v2/groups/"):
+		m.serveGroups(w, r, strings.TrimPrefix(path, "/v2/groups"))
+	case path == "/v2/deployments" || strings.HasPrefix(path, "/v2/deployments/"):
+		m.serveDeployments(w, r, strings.TrimPrefix(path, "/v2/deployments"))
+	case path == "/v2/queue":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"queue": m.queue()})
+	case path == "/v2/tasks":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"tasks": []interface{}{}})
+	default:
+		writeMarathonError(w, http.StatusNotFound, "No fake Marathon endpoint for "+path)
+	}
+}
+
+// serveEvents streams the Marathon events until the client disconnects or
+// the server is closed
+func (m *marathonState) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeMarathonError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	events := make(chan string, 100)
+	m.server.lock.Lock()
+	if m.server.DisableEventStream {
+		m.server.lock.Unlock()
+		writeMarathonError(w, http.StatusServiceUnavailable, "Event stream is not available")
+		return
+	}
+	m.listeners[events] = true
+	m.server.lock.Unlock()
+
+	defer func() {
+		m.server.lock.Lock()
+		delete(m.listeners, events)
+		m.server.lock.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "event: event_stream_attached\ndata: %s\n\n", mustJSON(map[string]interface{}{
+		"eventType":     "event_stream_attached",
+		"remoteAddress": r.RemoteAddr,
+		"timestamp":     time.Now().UTC().Format(time.RFC3339Nano),
+	}))
+	flusher.Flush()
+
+	for {
+		select {
+		case event := <-events:
+			w.Write([]byte(event))
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-m.server.done:
+			return
+		}
+	}
+}
+
+// EventSubscribers returns the number of clients connected to the event stream
+// of the Marathon served on the given path
+func (s *Server) EventSubscribers(marathonPath string) int {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return len(m.listeners)
+}
+
+// broadcast sends an event to all subscribers. Must be called with the lock held.
+func (m *marathonState) broadcast(eventType string, payload map[string]interface{}) {
+	payload["eventType"] = eventType
+	payload["timestamp"] = time.Now().UTC().Format(time.RFC3339Nano)
+	event := fmt.Sprintf("event: %s\ndata: %s\n\n", eventType, mustJSON(payload))
+
+	for listener := range m.listeners {
+		select {
+		case listener <- event:
+		default:
+		}
+	}
+}
+
+func mustJSON(payload interface{}) string {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		panic(err)
+	}
+	return string(body)
+}
+
+// newVersion returns a unique Marathon version timestamp
+func (m *marathonState) newVersion() string {
+	m.server.sequence++
+	return time.Now().UTC().Add(time.Duration(m.server.sequence) * time.Microsecond).Format("2006-01-02T15:04:05.000000Z")
+}
+
+// HoldDeployments keeps all following deployments of the app or pod in
+// progress until they are cancelled, or released by calling it with false.
+// Restoring a previous version is not affected.
+func (s *Server) HoldDeployments(marathonPath, id string, hold bool) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	id = marathonID(id)
+	if hold {
+		m.held[id] = true
+		return
+	}
+
+	delete(m.held, id)
+	for _, deployment := range m.deployments {
+		for _, affected := range append(append([]string{}, deployment.apps...), deployment.pods...) {
+			if affected == id {
+				m.finishAfterDelay(deployment)
+			}
+		}
+	}
+}
+
+// deploy starts a deployment affecting the given apps and pods and completes
+// it after the configured delay. Must be called with the lock held.
+func (m *marathonState) deploy(apps []string, pods []string) *marathonDeployment {
+	return m.startDeployment(apps, pods, false)
+}
+
+// restore starts a deployment going back to versions deployed before, which
+// is not affected by failures or holds. Must be called with the lock held.
+func (m *marathonState) restore(apps []string, pods []string) *marathonDeployment {
+	return m.startDeployment(apps, pods, true)
+}
+
+func (m *marathonState) startDeployment(apps []string, pods []string, restore bool) *marathonDeployment {
+	deployment := &marathonDeployment{
+		id:      fmt.Sprintf("%08x-0000-4000-8000-%012x", time.Now().UnixNano()&0xffffffff, m.server.sequence+1),
+		version: m.newVersion(),
+		apps:    apps,
+		pods:    pods,
+	}
+	held := false
+	for _, id := range append(append([]string{}, apps...), pods...) {
+		if message, ok := m.failures[id]; ok && !restore {
+			deployment.failure = message
+		}
+		if m.held[id] && !restore {
+			held = true
+		}
+	}
+	m.deployments[deployment.id] = deployment
+
+	m.broadcast("deployment_info", map[string]interface{}{
+		"plan": map[string]interface{}{"id": deployment.id, "version": deployment.version},
+	})
+
+	if !held {
+		m.finishAfterDelay(deployment)
+	}
+
+	return deployment
+}
+
+// finishAfterDelay completes the deployment after the configured delay
+func (m *marathonState) finishAfterDelay(deployment *marathonDeployment) {
+	time.AfterFunc(m.server.DeploymentDelay, func() {
+		m.server.lock.Lock()
+		defer m.server.lock.Unlock()
+
+		m.finishDeployment(deployment)
+	})
+}
+
+// finishDeployment completes the deployment and notifies the subscribers.
+// Must be called with the lock held.
+func (m *marathonState) finishDeployment(deployment *marathonDeployment) {
+	if _, ok := m.deployments[deployment.id]; !ok {
+		return
+	}
+	delete(m.deployments, deployment.id)
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	for _, id := range deployment.apps {
+		app, ok := m.apps[id]
+		status := m.appStatus[id]
+		if !ok || status == nil {
+			continue
+		}
+
+		instances := jsonInt(app["instances"])
+		status.staged = 0
+		if deployment.failure != "" {
+			status.running = 0
+			status.healthy = 0
+			status.failure = map[string]interface{}{
+				"appId":     id,
+				"message":   deployment.failure,
+				"state":     "TASK_FAILED",
+				"taskId":    strings.Trim(strings.Replace(id, "/", "_", -1), "_") + ".instance-failed",
+				"timestamp": now,
+				"version":   app["version"],
+			}
+			continue
+		}
+
+		status.running = instances
+		status.healthy = 0
+		if checks, ok := app["healthChecks"].([]interface{}); ok && len(checks) > 0 {
+			status.healthy = instances
+		}
+	}
+
+	for _, id := range deployment.pods {
+		if pod, ok := m.pods[id]; ok {
+			m.podStatus[id] = m.newPodStatus(id, pod, deployment.failure, deployment.kept[id])
+		}
+	}
+
+	plan := map[string]interface{}{"id": deployment.id, "version": deployment.version}
+	if deployment.failure != "" {
+		m.broadcast("deployment_failed", map[string]interface{}{"id": deployment.id, "plan": plan})
+	} else {
+		m.broadcast("deployment_success", map[string]interface{}{"id": deployment.id, "plan": plan})
+	}
+}
+
+// deploymentsOf returns the deployments in progress affecting the app or pod
+func (m *marathonState) deploymentsOf(id string) []map[string]string {
+	ret := []map[string]string{}
+	for _, deployment := range m.deployments {
+		for _, affected := range append(append([]string{}, deployment.apps...), deployment.pods...) {
+			if affected == id {
+				ret = append(ret, map[string]string{"id": deployment.id})
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i]["id"] < ret[j]["id"] })
+	return ret
+}
+
+// FailHealthChecks makes the health checks of the given number of running
+// tasks of the app fail
+func (s *Server) FailHealthChecks(marathonPath, id string, count int) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if status, ok := m.appStatus[marathonID(id)]; ok {
+		status.healthy = status.running - count
+		if status.healthy < 0 {
+			status.healthy = 0
+		}
+	}
+}
+
+// DeclineOffers makes the launch queue report offers declined for the given
+// reasons (eg. InsufficientCpus) while the app or pod is being deployed
+func (s *Server) DeclineOffers(marathonPath, id string, reasons map[string]int) {
+	m := s.marathonAt(marathonPath)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	m.declines[marathonID(id)] = reasons
+}
+
+// queue returns the launch queue items of the apps and pods being deployed
+func (m *marathonState) queue() []map[string]interface{} {
+	ids := map[string]bool{}
+	for _, deployment := range m.deployments {
+		for _, id := range append(append([]string{}, deployment.apps...), deployment.pods...) {
+			ids[id] = true
+		}
+	}
+
+	ret := []map[string]interface{}{}
+	for id := range ids {
+		var count int
+		item := map[string]interface{}{
+			"role":  "*",
+			"since": time.Now().UTC().Format(time.RFC3339),
+			"delay": map[string]interface{}{"overdue": true, "timeLeftSeconds": 0},
+		}
+		if app, ok := m.apps[id]; ok {
+			count = jsonInt(app["instances"])
+			item["app"] = map[string]interface{}{"id": id}
+			if role, ok := app["role"]; ok {
+				item["role"] = role
+			}
+		} else if pod, ok := m.pods[id]; ok {
+			count = 1
+			if scaling, ok := pod["scaling"].(map[string]interface{}); ok {
+				count = jsonInt(scaling["instances"])
+			}
+			item["pod"] = map[string]interface{}{"id": id}
+		} else {
+			continue
+		}
+		item["count"] = count
+
+		var reasons []string
+		offers := 0
+		for reason, declined := range m.declines[id] {
+			reasons = append(reasons, reason)
+			offers += declined
+		}
+		sort.Strings(reasons)
+
+		steps := []map[string]interface{}{}
+		for _, reason := range reasons {
+			steps = append(steps, map[string]interface{}{
+				"reason":    reason,
+				"declined":  m.declines[id][reason],
+				"processed": offers,
+			})
+		}
+		item["processedOffersSummary"] = map[string]interface{}{
+			"processedOffersCount":    offers,
+			"unusedOffersCount":       offers,
+			"rejectSummaryLastOffers": steps,
+		}
+
+		ret = append(ret, item)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return fmt.Sprint(ret[i]["app"], ret[i]["pod"]) < fmt.Sprint(ret[j]["app"], ret[j]["pod"])
+	})
+	return ret
+}
+
+// checkLocked responds with a conflict if the app or pod is being deployed
+// and the request is not forced
+func (m *marathonState) checkLocked(w http.ResponseWriter, r *http.Request, id string) bool {
+	if r.URL.Query().Get("force") == "true" {
+		return false
+	}
+
+	deployments := m.deploymentsOf(id)
+	if len(deployments) == 0 {
+		return false
+	}
+
+	writeJSON(w, http.StatusConflict, map[string]interface{}{
+		"message":     "App is locked by one or more deployments. Override with the option '?force=true'.",
+		"deployments": deployments,
+	})
+	return true
+}
+
+func jsonInt(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case json.Number:
+		i, _ := strconv.Atoi(v.String())
+		return i
+	}
+	return 0
+}
+
+// appResponse returns the app definition together with its status
+func (m *marathonState) appResponse(id string) map[string]interface{} {
+	app := copyJSON(m.apps[id])
+	status := m.appStatus[id]
+
+	app["tasksStaged"] = status.staged
+	app["tasksRunning"] = status.running
+	app["tasksHealthy"] = status.healthy
+	app["tasksUnhealthy"] = 0
+	if checks, ok := app["healthChecks"].([]interface{}); ok && len(checks) > 0 {
+		app["tasksUnhealthy"] = status.running - status.healthy
+	}
+	app["deployments"] = m.deploymentsOf(id)
+	app["tasks"] = m.appTasks(id)
+	if status.failure != nil {
+		app["lastTaskFailure"] = status.failure
+	}
+
+	return app
+}
+
+// appTasks returns the tasks of the app, as many as it has staged and running
+// tasks. The tasks of the i-th instance run on the agent 10.0.0.<i+1>.
+func (m *marathonState) appTasks(id string) []map[string]interface{} {
+	app := m.apps[id]
+	status := m.appStatus[id]
+
+	hostPorts := 0
+	if definitions, ok := app["portDefinitions"].([]interface{}); ok {
+		hostPorts += len(definitions)
+	}
+	if container, ok := app["container"].(map[string]interface{}); ok {
+		if mappings, ok := container["portMappings"].([]interface{}); ok {
+			for _, mapping := range mappings {
+				if _, ok := mapping.(map[string]interface{})["hostPort"]; ok {
+					hostPorts++
+				}
+			}
+		}
+	}
+
+	containerNetwork := false
+	if networks, ok := app["networks"].([]interface{}); ok {
+		for _, network := range networks {
+			if network.(map[string]interface{})["mode"] == "container" {
+				containerNetwork = true
+			}
+		}
+	}
+
+	checks, _ := app["healthChecks"].([]interface{})
+
+	name := strings.Trim(strings.Replace(id, "/", "_", -1), "_")
+	startedAt := time.Now().UTC().Format(time.RFC3339Nano)
+
+	ret := []map[string]interface{}{}
+	for i := 0; i < status.running+status.staged; i++ {
+		host := fmt.Sprintf("10.0.0.%d", i+1)
+		ipAddress := host
+		if containerNetwork {
+			ipAddress = fmt.Sprintf("9.0.0.%d", i+1)
+		}
+
+		ports := []int{}
+		for j := 0; j < hostPorts; j++ {
+			ports = append(ports, 31000+i*10+j)
+		}
+
+		task := map[string]interface{}{
+			"id":          fmt.Sprintf("%s.instance-%d", name, i),
+			"appId":       id,
+			"host":        host,
+			"slaveId":     fmt.Sprintf("agent-%d", i+1),
+			"ports":       ports,
+			"ipAddresses": []interface{}{map[string]interface{}{"ipAddress": ipAddress, "protocol": "IPv4"}},
+			"stagedAt":    startedAt,
+			"state":       "TASK_STAGING",
+			"version":     app["version"],
+		}
+
+		if i < status.running {
+			task["state"] = "TASK_RUNNING"
+			task["startedAt"] = startedAt
+			if len(checks) > 0 {
+				task["healthCheckResults"] = []interface{}{
+					map[string]interface{}{"alive": i < status.healthy, "taskId": task["id"]},
+				}
+			}
+		}
+
+		ret = append(ret, task)
+	}
+	return ret
+}
+
+// marathonAppDefaults returns the fields Marathon fills in when they are
+// missing from an app definition. Host ports are not assigned.
+func marathonAppDefaults() map[string]interface{} {
+	return map[string]interface{}{
+		"instances":             1,
+		"cpus":                  1,
+		"mem":                   128,
+		"disk":                  0,
+		"gpus":                  0,
+		"executor":              "",
+		"constraints":           []interface{}{},
+		"fetch":                 []interface{}{},
+		"dependencies":          []interface{}{},
+		"healthChecks":          []interface{}{},
+		"readinessChecks":       []interface{}{},
+		"labels":                map[string]interface{}{},
+		"env":                   map[string]interface{}{},
+		"secrets":               map[string]interface{}{},
+		"backoffSeconds":        1,
+		"backoffFactor":         1.15,
+		"maxLaunchDelaySeconds": 3600,
+		"requirePorts":          false,
+		"killSelection":         "YOUNGEST_FIRST",
+		"role":                  "*",
+		"upgradeStrategy": map[string]interface{}{
+			"minimumHealthCapacity": 1,
+			"maximumOverCapacity":   1,
+		},
+		"unreachableStrategy": map[string]interface{}{
+			"inactiveAfterSeconds": 0,
+			"expungeAfterSeconds":  0,
+		},
+	}
+}
+
+// applyContainerDefaults fills in the fields Marathon adds to app containers
+func applyContainerDefaults(app map[string]interface{}) {
+	container, ok := app["container"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, ok := container["volumes"]; !ok {
+		container["volumes"] = []interface{}{}
+	}
+
+	docker, ok := container["docker"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	defaults := map[string]interface{}{
+		"forcePullImage": false,
+		"privileged":     false,
+		"parameters":     []interface{}{},
+	}
+	for k, v := range defaults {
+		if _, ok := docker[k]; !ok {
+			docker[k] = v
+		}
+	}
+}
+
+// translateIPAddress replaces the IP-per-task configuration by the container
+// network it stands for, like Marathon 1.5 and later do
+func translateIPAddress(app map[string]interface{}) {
+	ipAddress, ok := app["ipAddress"].(map[string]interface{})
+	delete(app, "ipAddress")
+	if !ok {
+		return
+	}
+
+	network := map[string]interface{}{"mode": "container"}
+	if name, ok := ipAddress["networkName"].(string); ok && name != "" {
+		network["name"] = name
+	}
+	if labels, ok := ipAddress["labels"]; ok {
+		network["labels"] = labels
+	}
+	app["networks"] = []interface{}{network}
+}
+
+// applyReadinessCheckDefaults fills in the fields Marathon adds to readiness
+// checks
+func applyReadinessCheckDefaults(app map[string]interface{}) {
+	checks, _ := app["readinessChecks"].([]interface{})
+	for _, check := range checks {
+		check, ok := check.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		defaults := map[string]interface{}{
+			"httpStatusCodesForReady": []interface{}{200},
+			"preserveLastResponse":    false,
+		}
+		for k, v := range defaults {
+			if _, ok := check[k]; !ok {
+				check[k] = v
+			}
+		}
+	}
+}
+
+// storeApp stores a new version of the app. Must be called with the lock held.
+func (m *marathonState) storeApp(id string, app map[string]interface{}, version string) {
+	app["id"] = id
+	if _, ok := app["role"]; !ok {
+		if role := m.enforcedRole(id); role != "" {
+			app["role"] = role
+		}
+	}
+	for k, v := range marathonAppDefaults() {
+		if _, ok := app[k]; !ok {
+			app[k] = v
+		}
+	}
+	translateIPAddress(app)
+	applyContainerDefaults(app)
+	applyReadinessCheckDefaults(app)
+	app["version"] = version
+
+	previous, exists := m.apps[id]
+	lastScalingAt, lastConfigChangeAt := version, version
+	if exists {
+		info, _ := previous["versionInfo"].(map[string]interface{})
+		lastConfigChangeAt, _ = info["lastConfigChangeAt"].(string)
+	}
+	app["versionInfo"] = map[string]interface{}{
+		"lastScalingAt":      lastScalingAt,
+		"lastConfigChangeAt": lastConfigChangeAt,
+	}
+	if !exists || appConfigChanged(previous, app) {
+		app["versionInfo"].(map[string]interface{})["lastConfigChangeAt"] = version
+	}
+
+	m.apps[id] = app
+	m.appVersions[id] = append(m.appVersions[id], copyJSON(app))
+
+	status, ok := m.appStatus[id]
+	if !ok {
+		status = &marathonAppStatus{}
+		m.appStatus[id] = status
+	}
+	status.staged = jsonInt(app["instances"])
+}
+
+// appConfigChanged checks if anything but the scale of an app changed
+func appConfigChanged(previous, app map[string]interface{}) bool {
+	a, b := copyJSON(previous), copyJSON(app)
+	for _, key := range []string{"instances", "version", "versionInfo"} {
+		delete(a, key)
+		delete(b, key)
+	}
+	return mustJSON(a) != mustJSON(b)
+}
+
+func (m *marathonState) serveApps(w http.ResponseWriter, r *http.Request, path string) {
+	if path == "" || path == "/" {
+		switch r.Method {
+		case http.MethodGet:
+			var ids []string
+			for id := range m.apps {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+
+			apps := []map[string]interface{}{}
+			for _, id := range ids {
+				apps = append(apps, m.appResponse(id))
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"apps": apps})
+
+		case http.MethodPost:
+			var app map[string]interface{}
+			if err := readJSON(r, &app); err != nil {
+				writeMarathonError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			rawID, _ := app["id"].(string)
+			id := marathonID(rawID)
+			if id == "/" {
+				writeMarathonError(w, http.StatusUnprocessableEntity, "Object is not valid: id must not be empty")
+				return
+			}
+			if _, exists := m.apps[id]; exists {
+				writeMarathonError(w, http.StatusConflict, "An app with id ["+id+"] already exists.")
+				return
+			}
+
+			deployment := m.deploy([]string{id}, nil)
+			m.storeApp(id, app, deployment.version)
+			w.Header().Set("Location", "/v2/apps"+id)
+			w.Header().Set("Marathon-Deployment-Id", deployment.id)
+			writeJSON(w, http.StatusCreated, m.appResponse(id))
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	// Split off the sub-resources of the app
+	id, action, version := marathonID(path), "", ""
+	parts := splitPath(path)
+	if n := len(parts); n > 1 {
+		switch {
+		case parts[n-1] == "restart" || parts[n-1] == "tasks" || parts[n-1] == "versions":
+			id, action = marathonID(strings.Join(parts[:n-1], "/")), parts[n-1]
+		case n > 2 && parts[n-2] == "versions":
+			id, action, version = marathonID(strings.Join(parts[:n-2], "/")), "versions", parts[n-1]
+		}
+	}
+
+	app, exists := m.apps[id]
+	if !exists && !(action == "" && r.Method == http.MethodPut) {
+		writeMarathonError(w, http.StatusNotFound, "App '"+id+"' does not exist")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"app": m.appResponse(id)})
+
+	case action == "" && r.Method == http.MethodPut:
+		var update map[string]interface{}
+		if err := readJSON(r, &update); err != nil {
+			writeMarathonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if m.checkLocked(w, r, id) {
+			return
+		}
+
+		// Updating to just a version restores the app definition of that version
+		if restoreVersion, ok := update["version"].(string); ok && len(update) == 1 {
+			var restored map[string]interface{}
+			for _, v := range m.appVersions[id] {
+				if v["version"] == restoreVersion {
+					restored = copyJSON(v)
+				}
+			}
+			if !exists || restored == nil {
+				writeMarathonError(w, http.StatusNotFound, "App '"+id+"' does not exist in version "+restoreVersion)
+				return
+			}
+
+			deployment := m.restore([]string{id}, nil)
+			m.storeApp(id, restored, deployment.version)
+			w.Header().Set("Marathon-Deployment-Id", deployment.id)
+			writeJSON(w, http.StatusOK, map[string]string{"deploymentId": deployment.id, "version": deployment.version})
+			return
+		}
+
+		// Without `partialUpdate=false` the fields are merged into the app
+		if exists && r.URL.Query().Get("partialUpdate") != "false" {
+			merged := copyJSON(app)
+			for k, v := range update {
+				if v == nil {
+					delete(merged, k)
+				} else {
+					merged[k] = v
+				}
+			}
+			update = merged
+		}
+
+		deployment := m.deploy([]string{id}, nil)
+		m.storeApp(id, update, deployment.version)
+
+		status := http.StatusOK
+		if !exists {
+			status = http.StatusCreated
+		}
+		w.Header().Set("Marathon-Deployment-Id", deployment.id)
+		writeJSON(w, status, map[string]string{"deploymentId": deployment.id, "version": deployment.version})
+
+	case action == "" && r.Method == http.MethodDelete:
+		if m.checkLocked(w, r, id) {
+			return
+		}
+		deployment := m.deploy(nil, nil)
+		delete(m.apps, id)
+		delete(m.appVersions, id)
+		delete(m.appStatus, id)
+		writeJSON(w, http.StatusOK, map[string]string{"deploymentId": deployment.id, "version": deployment.version})
+
+	case action == "restart" && r.Method == http.MethodPost:
+		if m.checkLocked(w, r, id) {
+			return
+		}
+		deployment := m.deploy([]string{id}, nil)
+		m.appStatus[id].staged = jsonInt(app["instances"])
+		writeJSON(w, http.StatusOK, map[string]string{"deploymentId": deployment.id, "version": deployment.version})
+
+	case action == "tasks" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"tasks": m.appTasks(id)})
+
+	case action == "versions" && version == "" && r.Method == http.MethodGet:
+		versions := []string{}
+		for i := len(m.appVersions[id]) - 1; i >= 0; i-- {
+			versions = append(versions, m.appVersions[id][i]["version"].(string))
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"versions": versions})
+
+	case action == "versions" && r.Method == http.MethodGet:
+		for _, v := range m.appVersions[id] {
+			if v["version"] == version {
+				writeJSON(w, http.StatusOK, v)
+				return
+			}
+		}
+		writeMarathonError(w, http.StatusNotFound, "App '"+id+"' does not exist in version "+version)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// newPodStatus returns the status of a deployed pod, with one instance per
+// scaled instance, starting with the kept ones. Must be called with the lock
+// held.
+func (m *marathonState) newPodStatus(id string, pod map[string]interface{}, failure string, kept []interface{}) map[string]interface{} {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	instances := 1
+	if scaling, ok := pod["scaling"].(map[string]interface{}); ok {
+		if _, ok := scaling["instances"]; ok {
+			instances = jsonInt(scaling["instances"])
+		}
+	}
+
+	status := map[string]interface{}{
+		"id":          id,
+		"spec":        copyJSON(pod),
+		"status":      "STABLE",
+		"statusSince": now,
+		"lastUpdated": now,
+		"lastChanged": now,
+		"instances":   []interface{}{},
+	}
+
+	if failure != "" {
+		instanceID := m.newPodInstanceID(id)
+		containers := []interface{}{}
+		specs, _ := pod["containers"].([]interface{})
+		for _, spec := range specs {
+			name, _ := spec.(map[string]interface{})["name"].(string)
+			containers = append(containers, map[string]interface{}{
+				"containerId":    instanceID + "." + name,
+				"lastKnownState": "TASK_FAILED",
+				"termination":    map[string]interface{}{"exitCode": 1, "message": failure},
+			})
+		}
+
+		status["status"] = "DEGRADED"
+		status["message"] = failure
+		status["terminationHistory"] = []interface{}{map[string]interface{}{
+			"instanceId":   instanceID,
+			"startedAt":    now,
+			"terminatedAt": now,
+			"message":      failure,
+			"containers":   containers,
+		}}
+		return status
+	}
+
+	for i := 0; i < instances; i++ {
+		instance := m.newPodInstance(id, pod, now)
+		if i < len(kept) {
+			instance = kept[i].(map[string]interface{})
+		}
+		status["instances"] = append(status["instances"].([]interface{}), instance)
+	}
+	return status
+}
+
+// onlyScalingChanged returns whether the pods only differ in their scaling,
+// which keeps the running instances
+func onlyScalingChanged(previous, pod map[string]interface{}) bool {
+	definitions := []string{}
+	for _, p := range []map[string]interface{}{previous, pod} {
+		definition := copyJSON(p)
+		delete(definition, "id")
+		delete(definition, "version")
+		delete(definition, "scaling")
+		data, _ := json.Marshal(definition)
+		definitions = append(definitions, string(data))
+	}
+	return definitions[0] == definitions[1]
+}
+
+func (m *marathonState) newPodInstanceID(id string) string {
+	m.server.sequence++
+	return fmt.Sprintf("%s.instance-%08x-0000-4000-8000-%012x", strings.Trim(strings.Replace(id, "/", "_", -1), "_"), time.Now().UnixNano()&0xffffffff, m.server.sequence)
+}
+
+func (m *marathonState) newPodInstance(id string, pod map[string]interface{}, now string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            m.newPodInstanceID(id),
+		"status":        "STABLE",
+		"statusSince":   now,
+		"agentHostname": "127.0.0.1",
+		"specReference": "/v2/pods" + id + "::versions/" + pod["version"].(string),
+		"lastUpdated":   now,
+		"lastChanged":   now,
+	}
+}
+
+func (m *marathonState) servePods(w http.ResponseWriter, r *http.Request, path string) {
+	if path == "" || path == "/" {
+		switch r.Method {
+		case http.MethodGet:
+			var ids []string
+			for id := range m.pods {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+
+			pods := []map[string]interface{}{}
+			for _, id := range ids {
+				pods = append(pods, m.pods[id])
+			}
+			writeJSON(w, http.StatusOK, pods)
+
+		case http.MethodPost:
+			var pod map[string]interface{}
+			if err := readJSON(r, &pod); err != nil {
+				writeMarathonError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			rawID, _ := pod["id"].(string)
+			id := marathonID(rawID)
+			if id == "/" {
+				writeMarathonError(w, http.StatusUnprocessableEntity, "Object is not valid: id must not be empty")
+				return
+			}
+			if _, exists := m.pods[id]; exists {
+				writeMarathonError(w, http.StatusConflict, "Pod "+id+" already exists")
+				return
+			}
+
+			deployment := m.storePod(id, pod)
+			w.Header().Set("Location", "/v2/pods"+id)
+			w.Header().Set("Marathon-Deployment-Id", deployment.id)
+			writeJSON(w, http.StatusCreated, pod)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	id, action := marathonID(path), ""
+	if idx := strings.Index(path, "::"); idx >= 0 {
+		id, action = marathonID(path[:idx]), path[idx+2:]
+	}
+
+	pod, exists := m.pods[id]
+	if !exists && !(action == "" && r.Method == http.MethodPut) {
+		writeMarathonError(w, http.StatusNotFound, "Pod '"+id+"' does not exist")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, pod)
+
+	case action == "" && r.Method == http.MethodPut:
+		var update map[string]interface{}
+		if err := readJSON(r, &update); err != nil {
+			writeMarathonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if m.checkLocked(w, r, id) {
+			return
+		}
+
+		deployment := m.storePod(id, update)
+		w.Header().Set("Marathon-Deployment-Id", deployment.id)
+		if exists {
+			writeJSON(w, http.StatusOK, update)
+		} else {
+			writeJSON(w, http.StatusCreated, update)
+		}
+
+	case action == "" && r.Method == http.MethodDelete:
+		if m.checkLocked(w, r, id) {
+			return
+		}
+		deployment := m.deploy(nil, []string{id})
+		delete(m.pods, id)
+		delete(m.podStatus, id)
+
+		// Marathon only responds with the deployment ID header
+		w.Header().Set("Marathon-Deployment-Id", deployment.id)
+		w.WriteHeader(http.StatusAccepted)
+
+	case action == "status" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, m.podStatus[id])
+
+	case action == "versions" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, []string{pod["version"].(string)})
+
+	case action == "instances" && r.Method == http.MethodDelete:
+		var instanceIDs []string
+		if err := readJSON(r, &instanceIDs); err != nil {
+			writeMarathonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		killed, err := m.killPodInstances(id, instanceIDs)
+		if err != nil {
+			writeMarathonError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, killed)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// storePod stores a new version of the pod and starts its deployment. Must
+// be called with the lock held.
+func (m *marathonState) storePod(id string, pod map[string]interface{}) *marathonDeployment {
+	deployment := m.deploy(nil, []string{id})
+
+	if previous, ok := m.pods[id]; ok && onlyScalingChanged(previous, pod) {
+		instances, _ := m.podStatus[id]["instances"].([]interface{})
+		deployment.kept = map[string][]interface{}{id: instances}
+	}
+
+	pod["id"] = id
+	pod["version"] = deployment.version
+	m.pods[id] = pod
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	status := m.podStatus[id]
+	if status == nil {
+		status = map[string]interface{}{"id": id, "instances": []interface{}{}}
+		m.podStatus[id] = status
+	}
+	status["spec"] = copyJSON(pod)
+	status["status"] = "DEGRADED"
+	status["statusSince"] = now
+
+	return deployment
+}
+
+// killPodInstances replaces the given instances of the pod by new ones. Must
+// be called with the lock held.
+func (m *marathonState) killPodInstances(id string, instanceIDs []string) ([]map[string]interface{}, error) {
+	status := m.podStatus[id]
+	instances, _ := status["instances"].([]interface{})
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	killed := []map[string]interface{}{}
+	for _, instanceID := range instanceIDs {
+		found := false
+		for i, instance := range instances {
+			if instance.(map[string]interface{})["id"] == instanceID {
+				replacement := m.newPodInstance(id, m.pods[id], now)
+				replacement["status"] = "STAGING"
+				instances[i] = replacement
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Instance %s of pod %s does not exist", instanceID, id)
+		}
+
+		killed = append(killed, map[string]interface{}{
+			"instanceId": map[string]string{"idString": instanceID},
+			"agentInfo": map[string]interface{}{
+				"host":       "127.0.0.1",
+				"agentId":    "fake-agent",
+				"attributes": []string{},
+			},
+			"tasksMap":       map[string]interface{}{},
+			"runSpecVersion": now,
+			"state": map[string]interface{}{
+				"condition":   "Killing",
+				"since":       now,
+				"activeSince": now,
+			},
+		})
+	}
+
+	status["instances"] = instances
+	status["status"] = "DEGRADED"
+	m.stabilizeAfterDelay(id, status)
+	return killed, nil
+}
+
+// stabilizeAfterDelay marks the instances of the pod as stable after the
+// configured deployment delay, like they finished launching
+func (m *marathonState) stabilizeAfterDelay(id string, status map[string]interface{}) {
+	time.AfterFunc(m.server.DeploymentDelay, func() {
+		m.server.lock.Lock()
+		defer m.server.lock.Unlock()
+
+		// The pod was redeployed or removed in the meantime
+		if m.podStatus[id]["statusSince"] != status["statusSince"] {
+			return
+		}
+
+		instances, _ := status["instances"].([]interface{})
+		for _, instance := range instances {
+			instance.(map[string]interface{})["status"] = "STABLE"
+		}
+		status["status"] = "STABLE"
+	})
+}
+
+func (m *marathonState) serveDeployments(w http.ResponseWriter, r *http.Request, path string) {
+	if path == "" || path == "/" {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var ids []string
+		for id := range m.deployments {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		deployments := []map[string]interface{}{}
+		for _, id := range ids {
+			deployment := m.deployments[id]
+			deployments = append(deployments, map[string]interface{}{
+				"id":             deployment.id,
+				"version":        deployment.version,
+				"affectedApps":   append([]string{}, deployment.apps...),
+				"affectedPods":   append([]string{}, deployment.pods...),
+				"steps":          []interface{}{},
+				"currentActions": []interface{}{},
+				"currentStep":    1,
+				"totalSteps":     1,
+			})
+		}
+		writeJSON(w, http.StatusOK, deployments)
+		return
+	}
+
+	id := strings.Trim(path, "/")
+	deployment, exists := m.deployments[id]
+	if !exists {
+		writeMarathonError(w, http.StatusNotFound, "DeploymentPlan "+id+" does not exist")
+		return
+	}
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	// A forced delete just stops the deployment, otherwise it is rolled back
+	delete(m.deployments, id)
+	for _, appID := range deployment.apps {
+		if status, ok := m.appStatus[appID]; ok {
+			status.staged = 0
+		}
+	}
+	if r.URL.Query().Get("force") == "true" {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// Rolling back the deployment of a new app removes it
+	rollback := m.restore(deployment.apps, deployment.pods)
+	for _, appID := range deployment.apps {
+		versions := m.appVersions[appID]
+		if len(versions) > 1 {
+			m.storeApp(appID, copyJSON(versions[len(versions)-2]), rollback.version)
+		} else {
+			m.removeApp(appID)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"deploymentId": rollback.id, "version": rollback.version})
+}
+
+// removeApp deletes an app without a deployment, like the uninstall of a
+// package does. Must be called with the lock held.
+func (m *marathonState) removeApp(id string) {
+	delete(m.apps, id)
+	delete(m.appVersions, id)
+	delete(m.appStatus, id)
+}
+
+// enforcedRole returns the role enforced by the top-level group of the app or
+// pod, if any
+func (m *marathonState) enforcedRole(id string) string {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	if group, ok := m.groups["/"+parts[0]]; ok && group["enforceRole"] == true {
+		return parts[0]
+	}
+	return ""
+}
+
+// groupExists returns whether the group was created, or holds apps or pods
+func (m *marathonState) groupExists(id string) bool {
+	if _, ok := m.groups[id]; ok {
+		return true
+	}
+	for _, ids := range [][]string{keysOf(m.apps), keysOf(m.pods), keysOf(m.groups)} {
+		for _, child := range ids {
+			if strings.HasPrefix(child, id+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groupResponse returns the group together with the apps, pods and groups
+// directly within it
+func (m *marathonState) groupResponse(id string) map[string]interface{} {
+	group := map[string]interface{}{
+		"id":           id,
+		"enforceRole":  false,
+		"dependencies": []interface{}{},
+	}
+	if stored, ok := m.groups[id]; ok {
+		group = copyJSON(stored)
+	}
+
+	apps := []map[string]interface{}{}
+	for _, appID := range keysOf(m.apps) {
+		if isDirectChild(id, appID) {
+			apps = append(apps, m.appResponse(appID))
+		}
+	}
+	pods := []map[string]interface{}{}
+	for _, podID := range keysOf(m.pods) {
+		if isDirectChild(id, podID) {
+			pods = append(pods, copyJSON(m.pods[podID]))
+		}
+	}
+
+	children := map[string]bool{}
+	for _, ids := range [][]string{keysOf(m.apps), keysOf(m.pods), keysOf(m.groups)} {
+		for _, child := range ids {
+			if !strings.HasPrefix(child, id+"/") {
+				continue
+			}
+			parts := strings.Split(strings.TrimPrefix(child, id+"/"), "/")
+			if len(parts) > 1 || m.groups[child] != nil {
+				children[id+"/"+parts[0]] = true
+			}
+		}
+	}
+	groups := []map[string]interface{}{}
+	for _, groupID := range keysOf(children) {
+		groups = append(groups, m.groupResponse(groupID))
+	}
+
+	group["apps"] = apps
+	group["pods"] = pods
+	group["groups"] = groups
+	return group
+}
+
+func isDirectChild(parent, id string) bool {
+	return strings.HasPrefix(id, parent+"/") && !strings.Contains(strings.TrimPrefix(id, parent+"/"), "/")
+}
+
+// keysOf returns the sorted keys of the map
+func keysOf(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]map[string]interface{}:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]bool:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (m *marathonState) serveGroups(w http.ResponseWriter, r *http.Request, path string) {
+	var body map[string]interface{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := readJSON(r, &body); err != nil {
+			writeMarathonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	id := marathonID(path)
+	if r.Method == http.MethodPost {
+		groupID, _ := body["id"].(string)
+		id = marathonID(strings.TrimSuffix(path, "/") + "/" + strings.Trim(groupID, "/"))
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !m.groupExists(id) {
+			writeMarathonError(w, http.StatusNotFound, "Group '"+id+"' does not exist")
+			return
+		}
+		writeJSON(w, http.StatusOK, m.groupResponse(id))
+
+	case http.MethodPost, http.MethodPut:
+		if r.Method == http.MethodPost && m.groupExists(id) {
+			writeMarathonError(w, http.StatusConflict, "Group "+id+" is already created. Use PUT to change this group.")
+			return
+		}
+
+		group, ok := m.groups[id]
+		if !ok {
+			group = map[string]interface{}{
+				"id":           id,
+				"enforceRole":  false,
+				"dependencies": []interface{}{},
+			}
+		}
+		for _, field := range []string{"enforceRole", "dependencies"} {
+			if v, ok := body[field]; ok {
+				group[field] = v
+			}
+		}
+
+		deployment := m.deploy(nil, nil)
+		group["version"] = deployment.version
+		m.groups[id] = group
+
+		status := http.StatusOK
+		if r.Method == http.MethodPost {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, map[string]string{"deploymentId": deployment.id, "version": deployment.version})
+
+	case http.MethodDelete:
+		if !m.groupExists(id) {
+			writeMarathonError(w, http.StatusNotFound, "Group '"+id+"' does not exist")
+			return
+		}
+
+		for _, appID := range keysOf(m.apps) {
+			if strings.HasPrefix(appID, id+"/") {
+				m.removeApp(appID)
+			}
+		}
+		for _, podID := range keysOf(m.pods) {
+			if strings.HasPrefix(podID, id+"/") {
+				delete(m.pods, podID)
+				delete(m.podStatus, podID)
+			}
+		}
+		for _, groupID := range keysOf(m.groups) {
+			if groupID == id || strings.HasPrefix(groupID, id+"/") {
+				delete(m.groups, groupID)
+			}
+		}
+
+		deployment := m.deploy(nil, nil)
+		writeJSON(w, http.StatusOK, map[string]string{"deploymentId": deployment.id, "version": deployment.version})
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,445 @@
+package testserver
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Job run states reported by Metronome
+const (
+	JobRunActive  = "ACTIVE"
+	JobRunSuccess = "SUCCESS"
+	JobRunFailed  = "FAILED"
+)
+
+type metronomeState struct {
+	jobs      map[string]map[string]interface{}
+	schedules map[string]map[string]map[string]interface{}
+	runs      map[string]map[string]map[string]interface{}
+	history   map[string]map[string]interface{}
+	failRuns  map[string]bool
+	holdRuns  map[string]bool
+}
+
+func newMetronomeState() metronomeState {
+	return metronomeState{
+		jobs:      make(map[string]map[string]interface{}),
+		schedules: make(map[string]map[string]map[string]interface{}),
+		runs:      make(map[string]map[string]map[string]interface{}),
+		history:   make(map[string]map[string]interface{}),
+		failRuns:  make(map[string]bool),
+		holdRuns:  make(map[string]bool),
+	}
+}
+
+// Job returns the Metronome job with the given ID
+func (s *Server) Job(id string) (map[string]interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	job, ok := s.metronome.jobs[id]
+	return copyJSON(job), ok
+}
+
+// JobSchedule returns the schedule of a Metronome job
+func (s *Server) JobSchedule(jobID, scheduleID string) (map[string]interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	schedule, ok := s.metronome.schedules[jobID][scheduleID]
+	return copyJSON(schedule), ok
+}
+
+// FailJobRuns makes all following runs of the job fail, or succeed again
+func (s *Server) FailJobRuns(jobID string, fail bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.metronome.failRuns[jobID] = fail
+}
+
+// HoldJobRuns keeps all following runs of the job active until they are
+// stopped, or released by calling it with false
+func (s *Server) HoldJobRuns(jobID string, hold bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.metronome.holdRuns[jobID] = hold
+}
+
+// ActiveJobRuns returns the IDs of the active runs of the job
+func (s *Server) ActiveJobRuns(jobID string) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ids := []string{}
+	for id := range s.metronome.runs[jobID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// applyJobDefaults fills in the fields Metronome adds to a job definition
+func applyJobDefaults(job map[string]interface{}) {
+	if _, ok := job["labels"]; !ok {
+		job["labels"] = map[string]interface{}{}
+	}
+
+	run, ok := job["run"].(map[string]interface{})
+	if !ok {
+		run = make(map[string]interface{})
+		job["run"] = run
+	}
+
+	defaults := map[string]interface{}{
+		"disk":           0,
+		"gpus":           0,
+		"maxLaunchDelay": 3600,
+		"artifacts":      []interface{}{},
+		"env":            map[string]interface{}{},
+		"secrets":        map[string]interface{}{},
+		"volumes":        []interface{}{},
+		"networks":       []interface{}{},
+		"placement":      map[string]interface{}{"constraints": []interface{}{}},
+		"restart":        map[string]interface{}{"policy": "NEVER"},
+	}
+	for k, v := range defaults {
+		if _, ok := run[k]; !ok {
+			run[k] = v
+		}
+	}
+}
+
+func writeMetronomeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+func (s *Server) serveMetronome(w http.ResponseWriter, r *http.Request, path string) {
+	parts := splitPath(path)
+	if len(parts) == 0 || parts[0] != "jobs" {
+		writeMetronomeError(w, http.StatusNotFound, "No such endpoint "+path)
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			var ids []string
+			for id := range s.metronome.jobs {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+
+			jobs := []map[string]interface{}{}
+			for _, id := range ids {
+				jobs = append(jobs, s.embedJob(id, r))
+			}
+			writeJSON(w, http.StatusOK, jobs)
+
+		case http.MethodPost:
+			var job map[string]interface{}
+			if err := readJSON(r, &job); err != nil {
+				writeMetronomeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			id, _ := job["id"].(string)
+			if id == "" {
+				writeMetronomeError(w, http.StatusUnprocessableEntity, "Object is not valid: missing id")
+				return
+			}
+			if _, exists := s.metronome.jobs[id]; exists {
+				writeMetronomeError(w, http.StatusConflict, "Job with this id already exists")
+				return
+			}
+			applyJobDefaults(job)
+			s.metronome.jobs[id] = job
+			s.metronome.schedules[id] = make(map[string]map[string]interface{})
+			s.metronome.runs[id] = make(map[string]map[string]interface{})
+			s.metronome.history[id] = map[string]interface{}{
+				"successCount":           0,
+				"failureCount":           0,
+				"lastSuccessAt":          nil,
+				"lastFailureAt":          nil,
+				"successfulFinishedRuns": []interface{}{},
+				"failedFinishedRuns":     []interface{}{},
+			}
+			writeJSON(w, http.StatusCreated, job)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	id := parts[1]
+	if _, exists := s.metronome.jobs[id]; !exists {
+		writeMetronomeError(w, http.StatusNotFound, "Job not found")
+		return
+	}
+
+	if len(parts) > 2 {
+		switch parts[2] {
+		case "schedules":
+			s.serveMetronomeSchedules(w, r, id, parts[3:])
+		case "runs":
+			s.serveMetronomeRuns(w, r, id, parts[3:])
+		default:
+			writeMetronomeError(w, http.StatusNotFound, "No such endpoint "+path)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.embedJob(id, r))
+
+	case http.MethodPut:
+		var job map[string]interface{}
+		if err := readJSON(r, &job); err != nil {
+			writeMetronomeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		job["id"] = id
+		applyJobDefaults(job)
+		s.metronome.jobs[id] = job
+		writeJSON(w, http.StatusOK, job)
+
+	case http.MethodDelete:
+		if len(s.metronome.runs[id]) > 0 && r.URL.Query().Get("stopCurrentJobRuns") != "true" {
+			writeMetronomeError(w, http.StatusConflict, "Job has active runs")
+			return
+		}
+		delete(s.metronome.jobs, id)
+		delete(s.metronome.schedules, id)
+		delete(s.metronome.runs, id)
+		delete(s.metronome.history, id)
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// embedJob returns the job with the embedded details the request asks for
+func (s *Server) embedJob(id string, r *http.Request) map[string]interface{} {
+	job := copyJSON(s.metronome.jobs[id])
+
+	// Metronome expects `embed`, the generated client sends `embeded`
+	query := r.URL.Query()
+	for _, embed := range append(query["embed"], query["embeded"]...) {
+		switch embed {
+		case "activeRuns":
+			job["activeRuns"] = s.sortedRuns(id)
+		case "schedules":
+			job["schedules"] = s.sortedSchedules(id)
+		case "history":
+			job["history"] = copyJSON(s.metronome.history[id])
+		case "historySummary":
+			history := s.metronome.history[id]
+			job["historySummary"] = map[string]interface{}{
+				"successCount":  history["successCount"],
+				"failureCount":  history["failureCount"],
+				"lastSuccessAt": history["lastSuccessAt"],
+				"lastFailureAt": history["lastFailureAt"],
+			}
+		}
+	}
+
+	return job
+}
+
+func (s *Server) sortedSchedules(jobID string) []map[string]interface{} {
+	var ids []string
+	for id := range s.metronome.schedules[jobID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	ret := []map[string]interface{}{}
+	for _, id := range ids {
+		ret = append(ret, s.metronome.schedules[jobID][id])
+	}
+	return ret
+}
+
+func (s *Server) sortedRuns(jobID string) []map[string]interface{} {
+	var ids []string
+	for id := range s.metronome.runs[jobID] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	ret := []map[string]interface{}{}
+	for _, id := range ids {
+		ret = append(ret, s.metronome.runs[jobID][id])
+	}
+	return ret
+}
+
+func (s *Server) serveMetronomeSchedules(w http.ResponseWriter, r *http.Request, jobID string, parts []string) {
+	schedules := s.metronome.schedules[jobID]
+
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.sortedSchedules(jobID))
+
+		case http.MethodPost:
+			var schedule map[string]interface{}
+			if err := readJSON(r, &schedule); err != nil {
+				writeMetronomeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			id, _ := schedule["id"].(string)
+			if id == "" {
+				writeMetronomeError(w, http.StatusUnprocessableEntity, "Object is not valid: missing id")
+				return
+			}
+			if _, exists := schedules[id]; exists {
+				writeMetronomeError(w, http.StatusConflict, "Schedule with this id already exists")
+				return
+			}
+			schedules[id] = schedule
+			writeJSON(w, http.StatusCreated, schedule)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	id := parts[0]
+	schedule, exists := schedules[id]
+	if !exists || len(parts) > 1 {
+		writeMetronomeError(w, http.StatusNotFound, "Schedule not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, schedule)
+
+	case http.MethodPut:
+		var update map[string]interface{}
+		if err := readJSON(r, &update); err != nil {
+			writeMetronomeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		update["id"] = id
+		schedules[id] = update
+		writeJSON(w, http.StatusOK, update)
+
+	case http.MethodDelete:
+		delete(schedules, id)
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveMetronomeRuns(w http.ResponseWriter, r *http.Request, jobID string, parts []string) {
+	runs := s.metronome.runs[jobID]
+
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, s.sortedRuns(jobID))
+
+		case http.MethodPost:
+			now := time.Now().UTC()
+			id := now.Format("20060102150405") + s.nextID("")
+			run := map[string]interface{}{
+				"id":          id,
+				"jobId":       jobID,
+				"status":      JobRunActive,
+				"createdAt":   now.Format(time.RFC3339),
+				"completedAt": nil,
+				"tasks": []interface{}{map[string]interface{}{
+					"id":        jobID + "_" + id + ".task-1",
+					"status":    "TASK_RUNNING",
+					"startedAt": now.Format(time.RFC3339),
+				}},
+			}
+			runs[id] = run
+
+			status := JobRunSuccess
+			if s.metronome.failRuns[jobID] {
+				status = JobRunFailed
+			}
+			if !s.metronome.holdRuns[jobID] {
+				time.AfterFunc(s.DeploymentDelay, func() {
+					s.finishJobRun(jobID, id, status)
+				})
+			}
+			writeJSON(w, http.StatusCreated, run)
+
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	id := parts[0]
+	run, exists := runs[id]
+	if !exists {
+		writeMetronomeError(w, http.StatusNotFound, "Job run not found")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, run)
+
+	case len(parts) == 3 && parts[1] == "actions" && parts[2] == "stop" && r.Method == http.MethodPost:
+		s.finishJobRunLocked(jobID, id, JobRunFailed)
+		writeJSON(w, http.StatusOK, map[string]string{})
+
+	default:
+		writeMetronomeError(w, http.StatusNotFound, "No such job run endpoint")
+	}
+}
+
+// finishJobRun moves an active run to the job history
+func (s *Server) finishJobRun(jobID, runID, status string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.finishJobRunLocked(jobID, runID, status)
+}
+
+func (s *Server) finishJobRunLocked(jobID, runID, status string) {
+	run, ok := s.metronome.runs[jobID][runID]
+	if !ok {
+		return
+	}
+	delete(s.metronome.runs[jobID], runID)
+
+	finishedAt := time.Now().UTC().Format(time.RFC3339)
+	history := s.metronome.history[jobID]
+	tasks := []interface{}{}
+	for _, task := range run["tasks"].([]interface{}) {
+		tasks = append(tasks, task.(map[string]interface{})["id"])
+	}
+	entry := map[string]interface{}{
+		"id":         runID,
+		"createdAt":  run["createdAt"],
+		"finishedAt": finishedAt,
+		"tasks":      tasks,
+	}
+
+	if status == JobRunSuccess {
+		history["successCount"] = history["successCount"].(int) + 1
+		history["lastSuccessAt"] = finishedAt
+		history["successfulFinishedRuns"] = append([]interface{}{entry}, history["successfulFinishedRuns"].([]interface{})...)
+	} else {
+		history["failureCount"] = history["failureCount"].(int) + 1
+		history["lastFailureAt"] = finishedAt
+		history["failedFinishedRuns"] = append([]interface{}{entry}, history["failedFinishedRuns"].([]interface{})...)
+	}
+}
@@ -0,0 +1,126 @@
+package testserver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PlanComplete is the status of a finished SDK plan
+const PlanComplete = "COMPLETE"
+
+// SetPlanStatus changes the status of a plan of the SDK service. Plans of
+// installed packages are COMPLETE unless changed.
+func (s *Server) SetPlanStatus(service, plan, status string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	service = strings.Trim(service, "/")
+	if s.plans[service] == nil {
+		s.plans[service] = make(map[string]string)
+	}
+	s.plans[service][plan] = status
+}
+
+// serveSDK handles `{service}/v1/plans/{plan}[/restart]` where the service
+// name might contain slashes
+func (s *Server) serveSDK(w http.ResponseWriter, r *http.Request, path string) {
+	idx := strings.LastIndex(path, "/v1/plans")
+	service := strings.Trim(path[:idx], "/")
+	parts := splitPath(path[idx+len("/v1/plans"):])
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, installed := s.cosmos.installed["/"+service]
+	plans, known := s.plans[service]
+	if !installed && !known {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"message": fmt.Sprintf("Service %s not found", service),
+		})
+		return
+	}
+
+	if len(parts) == 0 && r.Method == http.MethodGet {
+		names := []string{"deploy"}
+		for name := range plans {
+			if name != "deploy" {
+				names = append(names, name)
+			}
+		}
+		writeJSON(w, http.StatusOK, names)
+		return
+	}
+
+	plan := parts[0]
+	status, ok := plans[plan]
+	if !ok {
+		status = PlanComplete
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		// Like the SDK, respond with 202 while the plan is in progress
+		code := http.StatusOK
+		if status != PlanComplete {
+			code = http.StatusAccepted
+		}
+		writeJSON(w, code, map[string]interface{}{
+			"phases": []map[string]interface{}{{
+				"id":       plan + "-phase",
+				"name":     plan,
+				"status":   status,
+				"strategy": "serial",
+				"steps":    []map[string]interface{}{},
+			}},
+			"strategy": "serial",
+			"status":   status,
+			"errors":   []string{},
+		})
+
+	case len(parts) == 2 && parts[1] == "restart" && r.Method == http.MethodPost:
+		writeJSON(w, http.StatusOK, map[string]string{"message": "Received cmd: restart"})
+
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "No such plan endpoint"})
+	}
+}
+
+// serveExhibitor emulates the Exhibitor node explorer the SDK meta-data is
+// kept in, which stores node contents as hex encoded bytes
+func (s *Server) serveExhibitor(w http.ResponseWriter, r *http.Request, path string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch {
+	case path == "/node-data" && r.Method == http.MethodGet:
+		key := r.URL.Query().Get("key")
+		writeJSON(w, http.StatusOK, map[string]string{
+			"bytes": fmt.Sprintf("% x", s.znodes[key]),
+			"str":   string(s.znodes[key]),
+			"stat":  "",
+		})
+
+	case strings.HasPrefix(path, "/znode/") && r.Method == http.MethodPut:
+		payload, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		body, err := hex.DecodeString(strings.Replace(string(payload), " ", "", -1))
+		if err != nil {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"succeeded": false, "message": err.Error()})
+			return
+		}
+
+		key, _ := url.PathUnescape(strings.TrimPrefix(path, "/znode"))
+		s.znodes[key] = body
+		writeJSON(w, http.StatusOK, map[string]interface{}{"succeeded": true, "message": "OK"})
+
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "No such exhibitor endpoint"})
+	}
+}
@@ -0,0 +1,81 @@
+package testserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+// Secret returns the value of the secret in the given store
+func (s *Server) Secret(store, path string) (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	value, ok := s.secrets[store+"/"+strings.Trim(path, "/")]
+	return value, ok
+}
+
+func writeSecretsError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"code":    strings.ToUpper(strings.Replace(http.StatusText(status), " ", "_", -1)),
+		"message": message,
+	})
+}
+
+// serveSecrets handles `{store}/{path}`, where the path might contain slashes
+func (s *Server) serveSecrets(w http.ResponseWriter, r *http.Request, path string) {
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || strings.Trim(parts[1], "/") == "" {
+		writeSecretsError(w, http.StatusNotFound, "Missing secret path")
+		return
+	}
+	key := parts[0] + "/" + strings.Trim(parts[1], "/")
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	value, exists := s.secrets[key]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeSecretsError(w, http.StatusNotFound, "Secret "+key+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, dcos.SecretsV1Secret{Value: value})
+
+	case http.MethodPut, http.MethodPatch:
+		var secret dcos.SecretsV1Secret
+		if err := readJSON(r, &secret); err != nil {
+			writeSecretsError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if r.Method == http.MethodPut && exists {
+			writeSecretsError(w, http.StatusConflict, "Secret "+key+" already exists")
+			return
+		}
+		if r.Method == http.MethodPatch && !exists {
+			writeSecretsError(w, http.StatusNotFound, "Secret "+key+" not found")
+			return
+		}
+
+		s.secrets[key] = secret.Value
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+	case http.MethodDelete:
+		if !exists {
+			writeSecretsError(w, http.StatusNotFound, "Secret "+key+" not found")
+			return
+		}
+		delete(s.secrets, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
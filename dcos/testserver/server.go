@@ -0,0 +1,301 @@
+// Package testserver implements an in-process fake of the DC/OS cluster APIs
+// used by the provider, so resources and modules can be tested without a
+// running cluster.
+//
+// The server keeps all of its state in memory. It emulates the subsets of
+// IAM, Secrets, Cosmos, Marathon, Metronome, EdgeLB and the SDK scheduler
+// APIs the provider resources rely on, including Marathon deployments that
+// complete asynchronously and are announced on the SSE event stream.
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultUser is the superuser existing on every new server
+	DefaultUser = "bootstrapuser"
+
+	// DefaultPassword is the password of the DefaultUser
+	DefaultPassword = "deleteme"
+
+	// DefaultDeploymentDelay is how long Marathon deployments take to complete
+	DefaultDeploymentDelay = 200 * time.Millisecond
+
+	// DefaultMarathonPath is where the root Marathon is served
+	DefaultMarathonPath = "/service/marathon"
+)
+
+// Server is a fake DC/OS cluster listening on a local address
+type Server struct {
+	// URL of the cluster, to be used as `dcos_url`
+	URL string
+
+	// DeploymentDelay is how long Marathon deployments take to complete
+	DeploymentDelay time.Duration
+
+	// Version is reported by /dcos-metadata/dcos-version.json
+	Version string
+
+	// DisableEventStream refuses Marathon event stream connections, like
+	// proxies that do not support server-sent events
+	DisableEventStream bool
+
+	// OnRequest is called with the `METHOD /path` of every request before it
+	// is served, eg. to expire the tokens in the middle of an apply
+	OnRequest func(request string)
+
+	server *httptest.Server
+	done   chan struct{}
+
+	lock      sync.Mutex
+	sequence  int
+	tokens    map[string]string
+	requests  []string
+	marathons map[string]*marathonState
+
+	iam       iamState
+	secrets   map[string]string
+	cosmos    cosmosState
+	metronome metronomeState
+	edgelb    map[string]map[string]interface{}
+	plans     map[string]map[string]string
+	znodes    map[string][]byte
+}
+
+// New starts a new fake cluster. It has to be stopped with Close.
+func New() *Server {
+	s := &Server{
+		DeploymentDelay: DefaultDeploymentDelay,
+		Version:         "1.13.0",
+		done:            make(chan struct{}),
+		tokens:          make(map[string]string),
+		marathons:       make(map[string]*marathonState),
+		iam:             newIAMState(),
+		secrets:         make(map[string]string),
+		cosmos:          newCosmosState(),
+		metronome:       newMetronomeState(),
+		edgelb:          make(map[string]map[string]interface{}),
+		plans:           make(map[string]map[string]string),
+		znodes:          make(map[string][]byte),
+	}
+
+	// Admin Router proxies the root Marathon on two paths
+	root := newMarathonState(s)
+	s.marathons["/marathon"] = root
+	s.marathons[DefaultMarathonPath] = root
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Close stops the server, terminating all open event streams
+func (s *Server) Close() {
+	close(s.done)
+	s.server.Close()
+}
+
+// AddMarathon serves an additional Marathon instance on the given path, like
+// a Marathon-on-Marathon installed as `/service/<name>`
+func (s *Server) AddMarathon(path string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path = "/" + strings.Trim(path, "/")
+	if _, ok := s.marathons[path]; !ok {
+		s.marathons[path] = newMarathonState(s)
+	}
+}
+
+// ProviderConfig returns a provider block logging into the server as the DefaultUser
+func (s *Server) ProviderConfig() string {
+	return fmt.Sprintf(`
+provider "dcos" {
+  dcos_url = %q
+  user     = %q
+  password = %q
+}
+`, s.URL, DefaultUser, DefaultPassword)
+}
+
+// ExpireTokens invalidates all ACS tokens handed out so far
+func (s *Server) ExpireTokens() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tokens = make(map[string]string)
+}
+
+// Requests returns the `METHOD /path` of every request received so far
+func (s *Server) Requests() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// nextID returns a unique identifier with the given prefix. Must be called
+// with the lock held.
+func (s *Server) nextID(prefix string) string {
+	s.sequence++
+	return fmt.Sprintf("%s%08d", prefix, s.sequence)
+}
+
+// issueToken creates a new ACS token for the user. Must be called with the
+// lock held.
+func (s *Server) issueToken(uid string) string {
+	token := s.nextID("token-")
+	s.tokens[token] = uid
+	return token
+}
+
+// isAuthorized checks the ACS token of the request
+func (s *Server) isAuthorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "token=")
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, ok := s.tokens[token]
+	return ok
+}
+
+// marathonFor returns the Marathon instance serving the path and the path
+// relative to it
+func (s *Server) marathonFor(path string) (*marathonState, string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Match the longest prefix, to give Marathon-on-Marathon precedence
+	prefixes := make([]string, 0, len(s.marathons))
+	for prefix := range s.marathons {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return s.marathons[prefix], strings.TrimPrefix(path, prefix)
+		}
+	}
+	return nil, ""
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	s.lock.Lock()
+	s.requests = append(s.requests, r.Method+" "+path)
+	s.lock.Unlock()
+
+	if s.OnRequest != nil {
+		s.OnRequest(r.Method + " " + path)
+	}
+
+	// Endpoints that do not require authentication
+	switch {
+	case path == "/acs/api/v1/auth/login":
+		s.serveLogin(w, r)
+		return
+	case path == "/dcos-metadata/dcos-version.json":
+		writeJSON(w, http.StatusOK, map[string]string{
+			"version":           s.Version,
+			"dcos-variant":      "enterprise",
+			"dcos-image-commit": "0000000000000000000000000000000000000000",
+			"bootstrap-id":      "0000000000000000000000000000000000000000",
+		})
+		return
+	case path == universePath:
+		s.serveUniverse(w, r)
+		return
+	}
+
+	if !s.isAuthorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{
+			"title":       "Invalid authentication credentials",
+			"description": "Please log in again",
+			"code":        "ERR_INVALID_AUTH_TOKEN",
+		})
+		return
+	}
+
+	if marathon, subPath := s.marathonFor(path); marathon != nil {
+		marathon.serveHTTP(w, r, subPath)
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(path, "/acs/api/v1/"):
+		s.serveIAM(w, r, strings.TrimPrefix(path, "/acs/api/v1"))
+	case strings.HasPrefix(path, "/secrets/v1/secret/"):
+		s.serveSecrets(w, r, strings.TrimPrefix(path, "/secrets/v1/secret/"))
+	case strings.HasPrefix(path, "/package/") || strings.HasPrefix(path, "/cosmos/service/"):
+		s.serveCosmos(w, r, path)
+	case strings.HasPrefix(path, "/service/metronome/v1/"):
+		s.serveMetronome(w, r, strings.TrimPrefix(path, "/service/metronome/v1"))
+	case strings.HasPrefix(path, "/service/edgelb/"):
+		s.serveEdgeLB(w, r, strings.TrimPrefix(path, "/service/edgelb"))
+	case strings.HasPrefix(path, "/exhibitor/exhibitor/v1/explorer/"):
+		s.serveExhibitor(w, r, strings.TrimPrefix(path, "/exhibitor/exhibitor/v1/explorer"))
+	case strings.HasPrefix(path, "/service/") && strings.Contains(path, "/v1/plans"):
+		s.serveSDK(w, r, strings.TrimPrefix(path, "/service/"))
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"message": fmt.Sprintf("No fake endpoint for %s %s", r.Method, path),
+		})
+	}
+}
+
+// writeJSON responds with the given payload
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if payload != nil {
+		json.NewEncoder(w).Encode(payload)
+	}
+}
+
+// readJSON parses the request body into the given payload
+func readJSON(r *http.Request, payload interface{}) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, payload)
+}
+
+// copyJSON returns a deep copy of a JSON object
+func copyJSON(input map[string]interface{}) map[string]interface{} {
+	if input == nil {
+		return nil
+	}
+
+	var ret map[string]interface{}
+	body, _ := json.Marshal(input)
+	json.Unmarshal(body, &ret)
+	return ret
+}
+
+// splitPath returns the non-empty segments of a path
+func splitPath(path string) []string {
+	var ret []string
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			ret = append(ret, part)
+		}
+	}
+	return ret
+}
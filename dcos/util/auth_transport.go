@@ -0,0 +1,193 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+// iamLoginPath is never re-authenticated, a 401 there means bad credentials
+const iamLoginPath = "/acs/api/v1/auth/login"
+
+// LoginFunc obtains a fresh ACS token
+type LoginFunc func(ctx context.Context) (string, error)
+
+// AuthTransport is a http.RoundTripper that authorizes requests with the
+// current ACS token. When a request is rejected with 401 it logs in again
+// and replays the request once with the new token. Concurrent requests
+// share a single login, and the new token is stored in Config if given.
+type AuthTransport struct {
+	Base   http.RoundTripper
+	Login  LoginFunc
+	Config *dcos.Config
+
+	lock       sync.RWMutex
+	token      string
+	refreshing *tokenRefresh
+}
+
+// tokenRefresh is a login in progress, shared by all requests waiting on it
+type tokenRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+/**
+ * InstallAuthTransport wraps the transport of the DC/OS HTTP client with an
+ * AuthTransport. All clients sharing `client.HTTPClient()` are covered.
+ */
+func InstallAuthTransport(client *dcos.APIClient, token string, login LoginFunc) (*AuthTransport, error) {
+	dcosTransport, ok := client.HTTPClient().Transport.(*dcos.DefaultTransport)
+	if !ok {
+		return nil, fmt.Errorf("Unexpected DC/OS HTTP transport %T", client.HTTPClient().Transport)
+	}
+
+	transport := &AuthTransport{
+		Base:   dcosTransport.Base,
+		Login:  login,
+		Config: dcosTransport.Config,
+		token:  token,
+	}
+	dcosTransport.Base = transport
+
+	return transport, nil
+}
+
+/**
+ * ACSToken returns the ACS token that is currently used by the client
+ */
+func ACSToken(client *dcos.APIClient) string {
+	for _, rt := range transportChain(client) {
+		if transport, ok := rt.(*AuthTransport); ok {
+			return transport.Token()
+		}
+	}
+
+	config := client.CurrentDCOSConfig()
+	return config.ACSToken()
+}
+
+// Token returns the current ACS token
+func (t *AuthTransport) Token() string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.token
+}
+
+func (t *AuthTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// refresh logs in again, unless another request already did so since
+// `staleToken` was used. Requests arriving while a login is in progress wait
+// for its token instead of logging in themselves.
+func (t *AuthTransport) refresh(ctx context.Context, staleToken string) (string, error) {
+	t.lock.Lock()
+	if t.token != staleToken {
+		token := t.token
+		t.lock.Unlock()
+		return token, nil
+	}
+
+	refresh := t.refreshing
+	if refresh == nil {
+		refresh = &tokenRefresh{done: make(chan struct{})}
+		t.refreshing = refresh
+		t.lock.Unlock()
+
+		// The login is placed without holding the lock, so requests with a
+		// valid token are not held up by it
+		log.Printf("[INFO] ACS token was rejected, logging in again")
+		refresh.token, refresh.err = t.Login(ctx)
+
+		t.lock.Lock()
+		if refresh.err == nil {
+			t.token = refresh.token
+			if t.Config != nil {
+				t.Config.SetACSToken(refresh.token)
+			}
+		}
+		t.refreshing = nil
+		t.lock.Unlock()
+		close(refresh.done)
+	} else {
+		t.lock.Unlock()
+
+		select {
+		case <-refresh.done:
+		case <-ctx.Done():
+			return "", fmt.Errorf("Unable to refresh ACS token: %s", ctx.Err().Error())
+		}
+	}
+
+	if refresh.err != nil {
+		return "", fmt.Errorf("Unable to refresh ACS token: %s", refresh.err.Error())
+	}
+	return refresh.token, nil
+}
+
+// RoundTrip places the request and replays it once if the token has expired
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Logins need no token, and must not wait for the login in progress
+	if strings.HasSuffix(req.URL.Path, iamLoginPath) {
+		return t.base().RoundTrip(req)
+	}
+	if t.Login == nil {
+		return t.roundTrip(req, t.Token())
+	}
+
+	// Make sure the body can be sent a second time
+	err := rewindableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	token := t.Token()
+	resp, err := t.roundTrip(req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	token, err = t.refresh(req.Context(), token)
+	if err != nil {
+		// Report the original 401 response
+		log.Printf("[WARN] %s", err.Error())
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := req.WithContext(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t.roundTrip(retry, token)
+}
+
+func (t *AuthTransport) roundTrip(req *http.Request, token string) (*http.Response, error) {
+	if token == "" {
+		return t.base().RoundTrip(req)
+	}
+
+	// Only modify a copy of the request
+	req2 := req.WithContext(req.Context())
+	req2.Header = make(http.Header, len(req.Header))
+	for k, s := range req.Header {
+		req2.Header[k] = append([]string(nil), s...)
+	}
+	req2.Header.Set("Authorization", fmt.Sprintf("token=%s", token))
+
+	return t.base().RoundTrip(req2)
+}
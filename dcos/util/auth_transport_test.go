@@ -0,0 +1,125 @@
+package util
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+/**
+ * Test that an expired token is refreshed and the request replayed once
+ */
+func TestAuthTransportRefresh(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token=fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	transport := &AuthTransport{
+		Login: func(ctx context.Context) (string, error) {
+			logins++
+			return "fresh", nil
+		},
+		token: "expired",
+	}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if string(body) != "payload" {
+		t.Errorf("Request body was not replayed, got '%s'", body)
+	}
+	if logins != 1 {
+		t.Errorf("Expected 1 login, got %d", logins)
+	}
+	if transport.Token() != "fresh" {
+		t.Errorf("Token was not updated")
+	}
+}
+
+/**
+ * Test that concurrent requests share a single login, which does not hold up
+ * reading the token, and that the new token is stored in the configuration
+ */
+func TestAuthTransportConcurrentRefresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token=fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	var logins int32
+	loggingIn := make(chan struct{})
+	release := make(chan struct{})
+	config := dcos.NewConfig(nil)
+	transport := &AuthTransport{
+		Login: func(ctx context.Context) (string, error) {
+			if atomic.AddInt32(&logins, 1) == 1 {
+				close(loggingIn)
+			}
+			<-release
+			return "fresh", nil
+		},
+		Config: config,
+		token:  "expired",
+	}
+	client := &http.Client{Transport: transport}
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err.Error())
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+
+	<-loggingIn
+	if transport.Token() != "expired" {
+		t.Errorf("Expected the token to be readable during the login")
+	}
+	close(release)
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		if status != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", status)
+		}
+	}
+	if logins != 1 {
+		t.Errorf("Expected 1 login, got %d", logins)
+	}
+	if config.ACSToken() != "fresh" {
+		t.Errorf("Token was not stored in the configuration, got '%s'", config.ACSToken())
+	}
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,24 +22,24 @@ func DCOSHTTPClient(client *dcos.APIClient) *http.Client {
 	return client.HTTPClient()
 }
 
-func DCOSNewRequest(client *dcos.APIClient, method, url string, body io.Reader) (*http.Request, error) {
+func DCOSNewRequest(ctx context.Context, client *dcos.APIClient, method, url string, body io.Reader) (*http.Request, error) {
 	config := client.CurrentDCOSConfig()
-	request, err := http.NewRequest(method, fmt.Sprintf("%s%s", config.URL(), url), body)
+	request, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", config.URL(), url), body)
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Add("Authorization", fmt.Sprintf("token=%s", config.ACSToken()))
+	request.Header.Add("Authorization", fmt.Sprintf("token=%s", ACSToken(client)))
 	return request, nil
 }
 
 /**
  * Get the DC/OS version from /dcos-metadata/dcos-version.json
  */
-func DCOSGetVersion(client *dcos.APIClient) (DCOSVersionSpec, error) {
+func DCOSGetVersion(ctx context.Context, client *dcos.APIClient) (DCOSVersionSpec, error) {
 	var ver DCOSVersionSpec
 
 	http := DCOSHTTPClient(client)
-	req, err := DCOSNewRequest(client, "GET", "/dcos-metadata/dcos-version.json", nil)
+	req, err := DCOSNewRequest(ctx, client, "GET", "/dcos-metadata/dcos-version.json", nil)
 	if err != nil {
 		return ver, fmt.Errorf("Unable to create request: %s", err.Error())
 	}
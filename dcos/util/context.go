@@ -0,0 +1,107 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+/**
+ * TimeoutContext returns a context that expires with the timeout the user has
+ * configured for the given lifecycle operation (eg. schema.TimeoutCreate)
+ */
+func TimeoutContext(d *schema.ResourceData, operation string) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), d.Timeout(operation))
+}
+
+/**
+ * StepError explains that the given step failed because the operation timed
+ * out, or returns the error as-is if the context is still valid
+ */
+func StepError(ctx context.Context, step string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("Timed out while %s: %s", step, err.Error())
+	}
+	if ctx.Err() == context.Canceled {
+		return fmt.Errorf("Cancelled while %s: %s", step, err.Error())
+	}
+
+	return err
+}
+
+/**
+ * RetryContext works like resource.Retry, but gives up when the context
+ * expires, even if the given timeout has not elapsed yet
+ */
+func RetryContext(ctx context.Context, timeout time.Duration, f resource.RetryFunc) error {
+	capped := false
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+			capped = true
+		}
+	}
+	if timeout <= 0 {
+		return ctx.Err()
+	}
+
+	var finished int32
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		if err := ctx.Err(); err != nil {
+			atomic.StoreInt32(&finished, 1)
+			return resource.NonRetryableError(err)
+		}
+		ret := f()
+		if ret == nil || !ret.Retryable {
+			atomic.StoreInt32(&finished, 1)
+		}
+		return ret
+	})
+
+	// Make sure the context reports the deadline when the retries ran out
+	// because of it
+	if err != nil && capped && atomic.LoadInt32(&finished) == 0 {
+		<-ctx.Done()
+	}
+	return err
+}
+
+/**
+ * ContextTransport attaches a context to all requests placed through it, for
+ * clients that do not accept contexts themselves (eg. go-marathon)
+ */
+type ContextTransport struct {
+	Context context.Context
+	Base    http.RoundTripper
+}
+
+func (t *ContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.Base.RoundTrip(req.WithContext(t.Context))
+}
+
+/**
+ * ContextHTTPClient returns a copy of the HTTP client whose requests are
+ * cancelled when the context expires
+ */
+func ContextHTTPClient(ctx context.Context, client *http.Client) *http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	ret := *client
+	ret.Transport = &ContextTransport{
+		Context: ctx,
+		Base:    transport,
+	}
+	return &ret
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+/**
+ * Test that retries stop when the context expires and the step is reported
+ */
+func TestRetryContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := RetryContext(ctx, time.Minute, func() *resource.RetryError {
+		return resource.RetryableError(errors.New("still waiting"))
+	})
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("Retries did not stop with the context")
+	}
+
+	err = StepError(ctx, "waiting for the plan", err)
+	if !strings.HasPrefix(err.Error(), "Timed out while waiting for the plan: ") {
+		t.Errorf("Unexpected error: %s", err.Error())
+	}
+}
+
+/**
+ * Test that errors pass through while the context is still valid
+ */
+func TestStepErrorPassthrough(t *testing.T) {
+	err := StepError(context.Background(), "reading", errors.New("boom"))
+	if err.Error() != "boom" {
+		t.Errorf("Unexpected error: %s", err.Error())
+	}
+	if StepError(context.Background(), "reading", nil) != nil {
+		t.Errorf("Expected no error")
+	}
+}
+
+/**
+ * Test that requests placed through a context-bound client are cancelled
+ */
+func TestContextHTTPClient(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	client := ContextHTTPClient(ctx, &http.Client{})
+	_, err := client.Get(server.URL)
+	if err == nil {
+		t.Fatalf("Expected the request to be cancelled")
+	}
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Errorf("Expected the context to have expired")
+	}
+}
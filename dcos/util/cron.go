@@ -0,0 +1,206 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+ * CronSchedule is a parsed cron expression in the five field UNIX format
+ * Metronome accepts: minute, hour, day of month, month and day of week
+ */
+type CronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// Days match if either day field matches, unless one of them is `*`
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var (
+	cronMinute     = cronField{name: "minute", min: 0, max: 59}
+	cronHour       = cronField{name: "hour", min: 0, max: 23}
+	cronDayOfMonth = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth      = cronField{name: "month", min: 1, max: 12, names: []string{
+		"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC",
+	}}
+	// 7 is accepted for Sunday as well
+	cronDayOfWeek = cronField{name: "day of week", min: 0, max: 7, names: []string{
+		"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT",
+	}}
+)
+
+/**
+ * ParseCron parses the given cron expression
+ */
+func ParseCron(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Expecting 5 fields (minute, hour, day of month, month, day of week), got %d", len(fields))
+	}
+
+	var err error
+	schedule := &CronSchedule{
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}
+
+	if schedule.minute, err = cronMinute.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = cronHour.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, err = cronDayOfMonth.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = cronMonth.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, err = cronDayOfWeek.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// Sunday is both 0 and 7
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+/**
+ * parse returns the bit set of the values matching a comma separated list of
+ * `*`, values and ranges, each with an optional step
+ */
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			rangeExpr = part[:idx]
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("Invalid step %q in %s field %q", part[idx+1:], f.name, expr)
+			}
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if end, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("Invalid range %q in %s field", rangeExpr, f.name)
+			}
+		default:
+			var err error
+			if start, err = f.value(rangeExpr); err != nil {
+				return 0, err
+			}
+			// A single value with a step runs until the end of the range
+			if step == 1 {
+				end = start
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+/**
+ * value parses a single number or name of the field
+ */
+func (f cronField) value(expr string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(expr, name) {
+			return f.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid value %q in %s field", expr, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("Value %d of %s field is out of range %d-%d", v, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+/**
+ * Next returns the first time the schedule fires after the given time, in the
+ * location of that time. The zero time is returned if the schedule never
+ * fires, eg. on February 30th.
+ */
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+
+	// Leap days repeat at least every 8 years
+	limit := t.Year() + 8
+
+	for t.Year() <= limit {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			// The next hour does not exist when clocks are set forward, and
+			// resolves to the same time. Counting the minutes to the next
+			// hour keeps to the zone, unlike truncating, which works in UTC
+			// and misses the hour in zones like -03:30.
+			if !next.After(t) {
+				next = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			}
+			t = next
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	dayOfMonth := c.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
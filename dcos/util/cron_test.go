@@ -0,0 +1,81 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+/**
+ * Test rejecting invalid cron expressions
+ */
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"* * * FOO *",
+	} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
+
+/**
+ * Test computing the next fire times of cron expressions
+ */
+func TestCronScheduleNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Unable to load time zone: %s", err.Error())
+	}
+	stJohns, err := time.LoadLocation("America/St_Johns")
+	if err != nil {
+		t.Fatalf("Unable to load time zone: %s", err.Error())
+	}
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("Unable to load time zone: %s", err.Error())
+	}
+
+	tests := []struct {
+		spec  string
+		after time.Time
+		next  time.Time
+	}{
+		{"0 2 * * *", time.Date(2019, 1, 1, 2, 0, 0, 0, time.UTC), time.Date(2019, 1, 2, 2, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2019, 1, 1, 10, 7, 30, 0, time.UTC), time.Date(2019, 1, 1, 10, 15, 0, 0, time.UTC)},
+		{"30 9 * * MON-FRI", time.Date(2019, 1, 4, 10, 0, 0, 0, time.UTC), time.Date(2019, 1, 7, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 6, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches if both are restricted
+		{"0 0 13 * 5", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 4, 0, 0, 0, 0, time.UTC)},
+		// 2:30 does not exist when clocks are turned forward
+		{"30 2 * * *", time.Date(2019, 3, 30, 12, 0, 0, 0, berlin), time.Date(2019, 4, 1, 2, 30, 0, 0, berlin)},
+		// Hours start on the half hour in UTC in zones like -03:30 and +05:30
+		{"0 3 * * *", time.Date(2019, 3, 10, 0, 30, 0, 0, stJohns), time.Date(2019, 3, 10, 3, 0, 0, 0, stJohns)},
+		{"0 */2 * * *", time.Date(2019, 1, 1, 10, 45, 0, 0, kolkata), time.Date(2019, 1, 1, 12, 0, 0, 0, kolkata)},
+		{"0 0 30 2 *", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCron(test.spec)
+		if err != nil {
+			t.Errorf("Unable to parse %q: %s", test.spec, err.Error())
+			continue
+		}
+		if next := schedule.Next(test.after); !next.Equal(test.next) {
+			t.Errorf("Expected %q to fire at %s after %s, got %s", test.spec, test.next, test.after, next)
+		}
+	}
+}
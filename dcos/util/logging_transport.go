@@ -0,0 +1,126 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+// maxLoggedBodySize limits how much of a body is written to the log
+const maxLoggedBodySize = 64 * 1024
+
+// LoggingTransport is a http.RoundTripper that logs every request and
+// response placed against the cluster, with sensitive data redacted.
+type LoggingTransport struct {
+	Base http.RoundTripper
+}
+
+/**
+ * InstallLoggingTransport places the LoggingTransport right in front of the
+ * network transport of the DC/OS HTTP client, so every retry is logged
+ */
+func InstallLoggingTransport(client *dcos.APIClient) error {
+	chain := transportChain(client)
+	if len(chain) < 2 {
+		return fmt.Errorf("Unexpected DC/OS HTTP transport %T", client.HTTPClient().Transport)
+	}
+
+	// The transport wrapping the network transport
+	switch t := chain[len(chain)-2].(type) {
+	case *dcos.DefaultTransport:
+		t.Base = &LoggingTransport{Base: t.Base}
+	case *AuthTransport:
+		t.Base = &LoggingTransport{Base: t.Base}
+	case *RetryTransport:
+		t.Base = &LoggingTransport{Base: t.Base}
+	default:
+		return fmt.Errorf("Unexpected DC/OS HTTP transport %T", t)
+	}
+
+	return nil
+}
+
+func (t *LoggingTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip logs the request, places it and logs the response
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+
+	err := rewindableBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			reqBody, _ = ioutil.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	log.Printf("[DEBUG] DC/OS HTTP request: %s %s\n%s%s",
+		req.Method, req.URL, formatHeaders(req.Header), formatBody(req.URL.Path, reqBody))
+
+	start := time.Now()
+	resp, err := t.base().RoundTrip(req)
+	latency := time.Since(start)
+
+	if err != nil {
+		log.Printf("[DEBUG] DC/OS HTTP request %s %s failed after %s: %s", req.Method, req.URL, latency, err.Error())
+		return resp, err
+	}
+
+	// Never consume event streams, they do not end
+	var respBody []byte
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		respBody, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	}
+
+	log.Printf("[DEBUG] DC/OS HTTP response: %s %s responded with %s in %s\n%s%s",
+		req.Method, req.URL, resp.Status, latency, formatHeaders(resp.Header), formatBody(req.URL.Path, respBody))
+
+	return resp, nil
+}
+
+// formatHeaders prints the headers, without credentials
+func formatHeaders(headers http.Header) string {
+	var out strings.Builder
+	for name, values := range headers {
+		for _, value := range values {
+			if name == "Authorization" || name == "Cookie" || name == "Set-Cookie" {
+				value = RedactedValue
+			}
+			fmt.Fprintf(&out, "%s: %s\n", name, value)
+		}
+	}
+	return out.String()
+}
+
+// formatBody prints the redacted body, truncated if too long
+func formatBody(path string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	redacted := RedactBody(path, body)
+	if len(redacted) > maxLoggedBodySize {
+		redacted = redacted[:maxLoggedBodySize] + "... (truncated)"
+	}
+	return "\n" + redacted
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces sensitive values in logs
+const RedactedValue = "<redacted>"
+
+var (
+	sensitiveKeyRegexp  = regexp.MustCompile(`(?i)(password|passwd|secret|token|private_?key|credential)`)
+	sensitivePairRegexp = regexp.MustCompile(`(?i)([\w-]*(?:password|passwd|secret|token|private_?key|credential)[\w-]*["']?\s*[:=]\s*["']?)[^\s&"',;]+`)
+	privateKeyRegexp    = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// secretsAPIPath serves secret values, so its payloads are never logged
+const secretsAPIPath = "/secrets/v1/secret/"
+
+/**
+ * RedactJSON returns a copy of the given JSON value, with every string value
+ * of a sensitive-looking key replaced, including those nested below it
+ */
+func RedactJSON(input interface{}) interface{} {
+	switch value := input.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			if sensitiveKeyRegexp.MatchString(k) {
+				ret[k] = redactAll(v)
+			} else {
+				ret[k] = RedactJSON(v)
+			}
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(value))
+		for i, v := range value {
+			ret[i] = RedactJSON(v)
+		}
+		return ret
+	case string:
+		return privateKeyRegexp.ReplaceAllString(value, RedactedValue)
+	default:
+		return value
+	}
+}
+
+/**
+ * redactAll returns a copy of the given JSON value with all of its string
+ * values replaced
+ */
+func redactAll(input interface{}) interface{} {
+	switch value := input.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			ret[k] = redactAll(v)
+		}
+		return ret
+	case []interface{}:
+		ret := make([]interface{}, len(value))
+		for i, v := range value {
+			ret[i] = redactAll(v)
+		}
+		return ret
+	case string:
+		return RedactedValue
+	default:
+		return value
+	}
+}
+
+/**
+ * RedactBody returns a printable version of an HTTP body sent to or received
+ * from the given path, without secret values, passwords or private keys.
+ * Bodies of the secrets API are left out entirely.
+ */
+func RedactBody(path string, body []byte) string {
+	if strings.Contains(path, secretsAPIPath) && len(body) > 0 {
+		return RedactedValue
+	}
+
+	var anyJson interface{}
+	if err := json.Unmarshal(body, &anyJson); err != nil {
+		redacted := privateKeyRegexp.ReplaceAllString(string(body), RedactedValue)
+		return sensitivePairRegexp.ReplaceAllString(redacted, "${1}"+RedactedValue)
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(RedactJSON(anyJson)); err != nil {
+		return RedactedValue
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
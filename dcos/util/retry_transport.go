@@ -0,0 +1,286 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+// DefaultRetryStatusCodes are retried if no other status codes are configured
+var DefaultRetryStatusCodes = []string{"502", "503"}
+
+// RetryTransport is a http.RoundTripper that retries requests failing with
+// a connection error or a retryable status code, using an exponential
+// backoff. It also limits the number of requests placed per second.
+//
+// Only idempotent requests, and POSTs which only read like rendering a Cosmos
+// package, are replayed once they were sent. Other requests are retried if
+// they failed before being sent, or if Admin Router answered with a gateway
+// error as the service never saw them, unless the caller opts in with an
+// Idempotency-Key header like for net/http.
+type RetryTransport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	WaitMin    time.Duration
+	WaitMax    time.Duration
+	RateLimit  float64
+
+	statusMatchers []string
+
+	lock        sync.Mutex
+	nextRequest time.Time
+}
+
+/**
+ * NewRetryTransport creates a RetryTransport for the given status codes,
+ * which are either exact codes like `503` or classes like `5xx`
+ */
+func NewRetryTransport(maxRetries int, waitMin, waitMax time.Duration, statusCodes []string, rateLimit float64) (*RetryTransport, error) {
+	if len(statusCodes) == 0 {
+		statusCodes = DefaultRetryStatusCodes
+	}
+
+	matchers := make([]string, 0, len(statusCodes))
+	for _, code := range statusCodes {
+		matcher := strings.ToLower(strings.TrimSpace(code))
+		if len(matcher) != 3 || !strings.ContainsAny(matcher[:1], "12345") {
+			return nil, fmt.Errorf("Invalid retry status code '%s'", code)
+		}
+		for _, c := range matcher[1:] {
+			if c != 'x' && (c < '0' || c > '9') {
+				return nil, fmt.Errorf("Invalid retry status code '%s'", code)
+			}
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	if waitMax < waitMin {
+		waitMax = waitMin
+	}
+
+	return &RetryTransport{
+		MaxRetries:     maxRetries,
+		WaitMin:        waitMin,
+		WaitMax:        waitMax,
+		RateLimit:      rateLimit,
+		statusMatchers: matchers,
+	}, nil
+}
+
+/**
+ * InstallRetryTransport places the RetryTransport right in front of the
+ * network transport of the DC/OS HTTP client
+ */
+func InstallRetryTransport(client *dcos.APIClient, transport *RetryTransport) error {
+	dcosTransport, ok := client.HTTPClient().Transport.(*dcos.DefaultTransport)
+	if !ok {
+		return fmt.Errorf("Unexpected DC/OS HTTP transport %T", client.HTTPClient().Transport)
+	}
+
+	transport.Base = dcosTransport.Base
+	dcosTransport.Base = transport
+	return nil
+}
+
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// isRetryableStatus checks the status code against the configured codes and classes
+func (t *RetryTransport) isRetryableStatus(statusCode int) bool {
+	code := strconv.Itoa(statusCode)
+	for _, matcher := range t.statusMatchers {
+		matches := len(code) == len(matcher)
+		for i := 0; matches && i < len(matcher); i++ {
+			matches = matcher[i] == 'x' || matcher[i] == code[i]
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the time to wait before the given retry attempt
+func (t *RetryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	wait := t.WaitMin << uint(attempt)
+	if wait > t.WaitMax || wait <= 0 {
+		wait = t.WaitMax
+	}
+
+	// Respect the server asking us to slow down, within the maximum wait time
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			retryAfter := time.Duration(seconds) * time.Second
+			if retryAfter > wait {
+				wait = retryAfter
+			}
+			if wait > t.WaitMax {
+				wait = t.WaitMax
+			}
+		}
+	}
+
+	return wait
+}
+
+// waitForRateLimit blocks until the next request may be placed
+func (t *RetryTransport) waitForRateLimit(ctx context.Context) error {
+	if t.RateLimit <= 0 {
+		return nil
+	}
+
+	t.lock.Lock()
+	now := time.Now()
+	wait := t.nextRequest.Sub(now)
+	if wait < 0 {
+		wait = 0
+		t.nextRequest = now
+	}
+	t.nextRequest = t.nextRequest.Add(time.Duration(float64(time.Second) / t.RateLimit))
+	t.lock.Unlock()
+
+	return sleepContext(ctx, wait)
+}
+
+// readOnlyPaths are POST endpoints which only read, like Cosmos describing packages
+var readOnlyPaths = []string{
+	"/package/describe",
+	"/package/list",
+	"/package/list-versions",
+	"/package/render",
+	"/package/search",
+	"/package/repository/list",
+	"/cosmos/service/describe",
+}
+
+// isIdempotent checks if the request can be replayed once it was sent
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+
+	for _, path := range readOnlyPaths {
+		if strings.HasSuffix(req.URL.Path, path) {
+			return true
+		}
+	}
+
+	_, ok := req.Header["Idempotency-Key"]
+	if !ok {
+		_, ok = req.Header["X-Idempotency-Key"]
+	}
+	return ok
+}
+
+/**
+ * isAdminRouterError checks if the gateway error was answered by Admin Router
+ * itself, with its HTML error page, as the request never reached the service
+ */
+func isAdminRouterError(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return !strings.Contains(resp.Header.Get("Content-Type"), "json")
+	}
+	return false
+}
+
+// requestBody returns the request body and how to get it again for a retry
+func requestBody(req *http.Request) (io.ReadCloser, func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return req.Body, req.GetBody, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	getBody := func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return ioutil.NopCloser(bytes.NewReader(body)), getBody, nil
+}
+
+// RoundTrip places the request and retries it on transient failures
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, getBody, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotent := isIdempotent(req)
+
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && getBody != nil {
+			body, err = getBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = t.waitForRateLimit(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		// The headers are the first thing written, possibly only to a buffer,
+		// so the request counts as sent from then on
+		var sent int32
+		trace := &httptrace.ClientTrace{
+			WroteHeaderField: func(string, []string) {
+				atomic.StoreInt32(&sent, 1)
+			},
+		}
+
+		// Every attempt is placed with its own copy, leaving the caller's request alone
+		attemptReq := req.Clone(httptrace.WithClientTrace(req.Context(), trace))
+		attemptReq.Body = body
+		attemptReq.GetBody = getBody
+
+		resp, err = t.base().RoundTrip(attemptReq)
+		if attempt >= t.MaxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if err != nil {
+			if !idempotent && atomic.LoadInt32(&sent) != 0 {
+				return resp, err
+			}
+			log.Printf("[WARN] %s %s failed, retrying: %s", req.Method, req.URL, err.Error())
+		} else if (idempotent && t.isRetryableStatus(resp.StatusCode)) || isAdminRouterError(resp) {
+			log.Printf("[WARN] %s %s responded with %s, retrying", req.Method, req.URL, resp.Status)
+		} else {
+			return resp, nil
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		err = sleepContext(req.Context(), wait)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * Test that transient errors are retried with the request body
+ */
+func TestRetryTransport(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport, err := NewRetryTransport(3, time.Millisecond, 10*time.Millisecond, []string{"5xx"}, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+/**
+ * Test that a POST which reached the service is not replayed, unless it only
+ * reads or Admin Router answered it
+ */
+func TestRetryTransportPost(t *testing.T) {
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.URL.Path]++
+		if body, _ := ioutil.ReadAll(r.Body); string(body) != "payload" {
+			t.Errorf("Expected the payload in every attempt, got '%s'", body)
+		}
+		if r.URL.Path == "/gateway" {
+			w.Header().Set("Content-Type", "text/html")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	transport, err := NewRetryTransport(3, time.Millisecond, 10*time.Millisecond, []string{"5xx"}, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	client := &http.Client{Transport: transport}
+
+	for path, expected := range map[string]int{"/service/marathon/v2/apps": 1, "/gateway": 4, "/package/render": 4} {
+		body := strings.NewReader("payload")
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		req.GetBody = nil
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected status 503 for %s, got %d", path, resp.StatusCode)
+		}
+		if attempts[path] != expected {
+			t.Errorf("Expected %d attempts for %s, got %d", expected, path, attempts[path])
+		}
+		if req.GetBody != nil {
+			t.Errorf("Expected the request of the caller to be left alone")
+		}
+	}
+}
+
+/**
+ * Test matching status codes and classes
+ */
+func TestRetryTransportStatusCodes(t *testing.T) {
+	transport, err := NewRetryTransport(1, time.Second, time.Second, []string{"502", "4xx"}, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	for code, expected := range map[int]bool{502: true, 503: false, 404: true, 200: false} {
+		if transport.isRetryableStatus(code) != expected {
+			t.Errorf("Unexpected result for status %d", code)
+		}
+	}
+
+	if _, err := NewRetryTransport(1, time.Second, time.Second, []string{"50"}, 0); err == nil {
+		t.Errorf("Expected an error for an invalid status code")
+	}
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -15,22 +16,33 @@ type SDKApiClient struct {
 	ClusterURL string
 	Client     *http.Client
 	Headers    map[string]string
+
+	ctx        context.Context
+	dcosClient *dcos.APIClient
 }
 
 /**
- * CreateSDKAPIClientFor initializes an SDKApiClient API
+ * CreateSDKAPIClient initializes an SDKApiClient API, placing all requests
+ * with the given context
  */
-func CreateSDKAPIClient(client *dcos.APIClient, appId string) *SDKApiClient {
+func CreateSDKAPIClient(ctx context.Context, client *dcos.APIClient, appId string) *SDKApiClient {
 	config := client.CurrentDCOSConfig()
-	headers := map[string]string{
-		"Authorization": fmt.Sprintf("token=%s", config.ACSToken()),
-	}
 
 	return &SDKApiClient{
 		AppID:      appId,
 		ClusterURL: config.URL(),
 		Client:     client.HTTPClient(),
-		Headers:    headers,
+		Headers:    map[string]string{},
+		ctx:        ctx,
+		dcosClient: client,
+	}
+}
+
+// authorize sets the current ACS token, since it might have been refreshed
+// after the client was created
+func (client *SDKApiClient) authorize(request *http.Request) {
+	if client.dcosClient != nil {
+		request.Header.Set("Authorization", fmt.Sprintf("token=%s", ACSToken(client.dcosClient)))
 	}
 }
 
@@ -55,8 +67,8 @@ func (client *SDKApiClient) postJSON(endpoint string, reqBody interface{}, respB
 	}
 
 	url := fmt.Sprintf("%s/service/%s/%s", client.ClusterURL, client.AppID, endpoint)
-	log.Printf("[TRACE] Placing POST request to %s with data: %s", url, string(payload))
-	request, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	log.Printf("[TRACE] Placing POST request to %s with data: %s", url, RedactBody(endpoint, payload))
+	request, err := http.NewRequestWithContext(client.ctx, "POST", url, bytes.NewReader(payload))
 	if err != nil {
 		return nil, fmt.Errorf("Unable to prepare request: %s", err.Error())
 	}
@@ -65,6 +77,7 @@ func (client *SDKApiClient) postJSON(endpoint string, reqBody interface{}, respB
 	for key, value := range client.Headers {
 		request.Header.Add(key, value)
 	}
+	client.authorize(request)
 
 	response, err := client.Client.Do(request)
 	if err != nil {
@@ -82,7 +95,7 @@ func (client *SDKApiClient) postJSON(endpoint string, reqBody interface{}, respB
 func (client *SDKApiClient) getJSON(endpoint string, respBody interface{}) (*http.Response, error) {
 	url := fmt.Sprintf("%s/service/%s/%s", client.ClusterURL, client.AppID, endpoint)
 	log.Printf("[TRACE] Placing GET request to %s", url)
-	request, err := http.NewRequest("GET", url, nil)
+	request, err := http.NewRequestWithContext(client.ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to prepare request: %s", err.Error())
 	}
@@ -91,6 +104,7 @@ func (client *SDKApiClient) getJSON(endpoint string, respBody interface{}) (*htt
 	for key, value := range client.Headers {
 		request.Header.Add(key, value)
 	}
+	client.authorize(request)
 
 	response, err := client.Client.Do(request)
 	if err != nil {
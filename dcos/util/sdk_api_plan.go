@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"strings"
 )
 
 type PlanStep struct {
@@ -34,8 +35,15 @@ type PlanRestartRequest struct {
  * Describe package
  */
 func (client *SDKApiClient) PlanGetStatus(plan string) (*PlansListResponse, error) {
+	return client.PlanGetStatusByPath(fmt.Sprintf("v1/plans/%s", plan))
+}
+
+/**
+ * Query the plan served on the given endpoint of the service (eg. v1/plan)
+ */
+func (client *SDKApiClient) PlanGetStatusByPath(planPath string) (*PlansListResponse, error) {
 	var jResp PlansListResponse
-	_, err := client.getJSON(fmt.Sprintf("v1/plans/%s", plan), &jResp)
+	_, err := client.getJSON(strings.TrimLeft(planPath, "/"), &jResp)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to place GET request: %s", err.Error())
 	}
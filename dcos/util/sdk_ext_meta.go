@@ -37,7 +37,7 @@ func (client *SDKApiClient) GetAllMeta() (map[string]interface{}, error) {
 	)
 
 	log.Printf("[TRACE] Placing GET request to %s", url)
-	request, err := http.NewRequest("GET", url, nil)
+	request, err := http.NewRequestWithContext(client.ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to prepare request: %s", err.Error())
 	}
@@ -113,7 +113,7 @@ func (client *SDKApiClient) SetAllMeta(meta map[string]interface{}) error {
 
 	payload := fmt.Sprintf("% x", configBytes)
 	log.Printf("[TRACE] Placing PUT request to %s with data: %s", url, payload)
-	request, err := http.NewRequest("PUT", url, bytes.NewReader([]byte(payload)))
+	request, err := http.NewRequestWithContext(client.ctx, "PUT", url, bytes.NewReader([]byte(payload)))
 	if err != nil {
 		return fmt.Errorf("Unable to prepare request: %s", err.Error())
 	}
@@ -0,0 +1,124 @@
+package util
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ServiceAccountLoginTokenLifetime is the validity of the login JWT that is
+// exchanged against an ACS token. IAM only checks it during the login call.
+const ServiceAccountLoginTokenLifetime = 5 * time.Minute
+
+// ServiceAccountSecret is the JSON document stored as a service account
+// secret in DC/OS and produced by `dcos_security_secret_service_account_secret`
+type ServiceAccountSecret struct {
+	Scheme        string `json:"scheme"`
+	UID           string `json:"uid"`
+	LoginEndpoint string `json:"login_endpoint"`
+	PrivateKey    string `json:"private_key"`
+}
+
+/**
+ * ParseServiceAccountSecret parses the service account secret JSON document
+ */
+func ParseServiceAccountSecret(contents string) (*ServiceAccountSecret, error) {
+	var sa ServiceAccountSecret
+
+	err := json.Unmarshal([]byte(contents), &sa)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse service account secret: %s", err.Error())
+	}
+	if sa.Scheme != "" && sa.Scheme != "RS256" {
+		return nil, fmt.Errorf("Unsupported service account scheme '%s'", sa.Scheme)
+	}
+	if sa.PrivateKey == "" {
+		return nil, fmt.Errorf("Service account secret does not contain a private key")
+	}
+
+	return &sa, nil
+}
+
+/**
+ * IsServiceAccountSecret checks if the given string looks like a service
+ * account secret JSON document instead of a PEM-encoded private key
+ */
+func IsServiceAccountSecret(contents string) bool {
+	return strings.HasPrefix(strings.TrimSpace(contents), "{")
+}
+
+/**
+ * ParseRSAPrivateKey parses a PEM encoded PKCS8 or PKCS1 RSA private key
+ */
+func ParseRSAPrivateKey(contents string) (*rsa.PrivateKey, error) {
+	var pKey *rsa.PrivateKey
+
+	// Try parsing block as PEM
+	block, _ := pem.Decode([]byte(contents))
+	if block == nil {
+		return nil, fmt.Errorf("Unable to decode private key PEM data")
+	}
+
+	// First try parsing it as PKCS8-encapsulated private key
+	parseResult, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		// If this fails, fall-back into parsing it as a plain PKCS1 private key
+		pKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse PKCS8 or PKCS1 private key")
+		}
+	} else {
+		var ok bool
+		pKey, ok = parseResult.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("Unable to parse RSA private key")
+		}
+	}
+
+	err = pKey.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Private key is not valid")
+	}
+
+	return pKey, nil
+}
+
+/**
+ * ServiceAccountLoginToken creates the RS256-signed JWT that IAM expects
+ * as the `token` field when a service account logs in
+ */
+func ServiceAccountLoginToken(uid string, pKey *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"uid": uid,
+		"exp": now.Add(ServiceAccountLoginTokenLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, pKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("Unable to sign login token: %s", err.Error())
+	}
+
+	return signingInput + "." + enc.EncodeToString(signature), nil
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * Test signing and verifying a service account login token
+ */
+func TestServiceAccountLoginToken(t *testing.T) {
+	pKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %s", err.Error())
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(pKey),
+	})
+	secret, _ := json.Marshal(ServiceAccountSecret{
+		Scheme:     "RS256",
+		UID:        "ci-account",
+		PrivateKey: string(keyPEM),
+	})
+
+	if !IsServiceAccountSecret(string(secret)) {
+		t.Errorf("Secret JSON was not detected")
+	}
+	if IsServiceAccountSecret(string(keyPEM)) {
+		t.Errorf("PEM key was detected as secret JSON")
+	}
+
+	sa, err := ParseServiceAccountSecret(string(secret))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	parsedKey, err := ParseRSAPrivateKey(sa.PrivateKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	now := time.Unix(1500000000, 0)
+	token, err := ServiceAccountLoginToken(sa.UID, parsedKey, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 token parts, got %d", len(parts))
+	}
+
+	var claims map[string]interface{}
+	claimBytes, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if err := json.Unmarshal(claimBytes, &claims); err != nil {
+		t.Fatalf("Unable to parse claims: %s", err.Error())
+	}
+	if claims["uid"] != "ci-account" {
+		t.Errorf("Unexpected uid claim: %v", claims["uid"])
+	}
+	if int64(claims["exp"].(float64)) != now.Add(ServiceAccountLoginTokenLifetime).Unix() {
+		t.Errorf("Unexpected exp claim: %v", claims["exp"])
+	}
+
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&pKey.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("Invalid signature: %s", err.Error())
+	}
+}
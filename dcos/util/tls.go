@@ -0,0 +1,130 @@
+package util
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+// clusterCAPath is where Admin Router serves the cluster CA certificate
+const clusterCAPath = "/ca/dcos-ca.crt"
+
+/**
+ * IsPEM checks if the given value contains PEM data instead of a file path
+ */
+func IsPEM(value string) bool {
+	return strings.Contains(value, "-----BEGIN ")
+}
+
+/**
+ * ReadPEM returns the PEM data of a value that is either PEM or a path to a PEM file
+ */
+func ReadPEM(value string) ([]byte, error) {
+	if IsPEM(value) {
+		return []byte(value), nil
+	}
+
+	contents, err := ioutil.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read '%s': %s", value, err.Error())
+	}
+
+	return contents, nil
+}
+
+/**
+ * FetchClusterCA downloads the cluster CA certificate. The connection is not
+ * verified, so the result must be checked against a fingerprint before use.
+ */
+func FetchClusterCA(clusterURL string) ([]byte, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	url := strings.TrimRight(clusterURL, "/") + clusterCAPath
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to fetch cluster CA certificate: %s", err.Error())
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Server on %s responded with %s", url, response.Status)
+	}
+
+	return ioutil.ReadAll(response.Body)
+}
+
+/**
+ * CertificateFingerprint returns the hex encoded SHA-256 fingerprint of the
+ * first certificate in the given PEM data
+ */
+func CertificateFingerprint(pemData []byte) (string, error) {
+	for {
+		var block *pem.Block
+		block, pemData = pem.Decode(pemData)
+		if block == nil {
+			return "", fmt.Errorf("No certificate found in PEM data")
+		}
+		if block.Type == "CERTIFICATE" {
+			sum := sha256.Sum256(block.Bytes)
+			return hex.EncodeToString(sum[:]), nil
+		}
+	}
+}
+
+/**
+ * VerifyCertificateFingerprint checks the first certificate in the PEM data
+ * against a SHA-256 fingerprint, given in hex with or without colons
+ */
+func VerifyCertificateFingerprint(pemData []byte, fingerprint string) error {
+	actual, err := CertificateFingerprint(pemData)
+	if err != nil {
+		return err
+	}
+
+	expected := strings.ToLower(strings.Replace(fingerprint, ":", "", -1))
+	if actual != expected {
+		return fmt.Errorf("Cluster CA certificate fingerprint '%s' does not match '%s'", actual, expected)
+	}
+
+	return nil
+}
+
+/**
+ * SetTLSClientConfig replaces the TLS configuration used by every request
+ * of the DC/OS HTTP client
+ */
+func SetTLSClientConfig(client *dcos.APIClient, tlsConfig *tls.Config) error {
+	transport, err := HTTPTransport(client)
+	if err != nil {
+		return err
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return nil
+}
+
+/**
+ * NewCertPool creates a certificate pool from PEM data
+ */
+func NewCertPool(pemData []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("No valid certificate found in CA bundle")
+	}
+	return pool, nil
+}
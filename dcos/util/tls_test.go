@@ -0,0 +1,210 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+/**
+ * testCertificate creates a self-signed client certificate and its key, both
+ * PEM encoded
+ */
+func testCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+/**
+ * Test pinning the cluster CA served by Admin Router to its fingerprint
+ */
+func TestFetchClusterCA(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != clusterCAPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	}))
+	defer server.Close()
+
+	caPEM, err := FetchClusterCA(server.URL + "/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	// Fingerprints are accepted in the colon separated format of openssl
+	pairs := []string{}
+	for i := 0; i < len(fingerprint); i += 2 {
+		pairs = append(pairs, strings.ToUpper(fingerprint[i:i+2]))
+	}
+	if err := VerifyCertificateFingerprint(caPEM, strings.Join(pairs, ":")); err != nil {
+		t.Errorf("Unexpected error: %s", err.Error())
+	}
+
+	err = VerifyCertificateFingerprint(caPEM, strings.Repeat("00", sha256.Size))
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("Expected a fingerprint mismatch, got %v", err)
+	}
+}
+
+/**
+ * Test that invalid PEM data is reported
+ */
+func TestReadPEMInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(path, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	pemData, err := ReadPEM(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if _, err := CertificateFingerprint(pemData); err == nil {
+		t.Errorf("Expected an error for a fingerprint of invalid PEM data")
+	}
+	if err := VerifyCertificateFingerprint(pemData, strings.Repeat("00", sha256.Size)); err == nil {
+		t.Errorf("Expected an error for verifying invalid PEM data")
+	}
+	if _, err := NewCertPool(pemData); err == nil {
+		t.Errorf("Expected an error for a CA bundle of invalid PEM data")
+	}
+
+	if _, err := ReadPEM(filepath.Join(dir, "missing.crt")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+}
+
+/**
+ * Test authenticating with a client certificate against a server requiring one
+ */
+func TestClientCertificate(t *testing.T) {
+	certPEM, keyPEM := testCertificate(t)
+
+	clientCAs, err := NewCertPool(certPEM)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	// The certificate is given inline, and the key as a file
+	certData, err := ReadPEM(string(certPEM))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	keyData, err := ReadPEM(keyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	cert, err := tls.X509KeyPair(certData, keyData)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	config := dcos.NewConfig(nil)
+	config.SetURL(server.URL)
+	client, err := dcos.NewClientWithConfig(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	// Without the client certificate the handshake is refused
+	err = SetTLSClientConfig(client, &tls.Config{RootCAs: rootCAs})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if resp, err := client.HTTPClient().Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Errorf("Expected the server to refuse a client without certificate")
+	}
+
+	err = SetTLSClientConfig(client, &tls.Config{RootCAs: rootCAs, Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	resp, err := client.HTTPClient().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if string(body) != "terraform" {
+		t.Errorf("Expected the server to see the client certificate, got '%s'", body)
+	}
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/dcos/client-go/dcos"
+)
+
+/**
+ * transportChain lists the transports of the DC/OS HTTP client, from the
+ * outermost to the innermost one
+ */
+func transportChain(client *dcos.APIClient) []http.RoundTripper {
+	var chain []http.RoundTripper
+
+	rt := client.HTTPClient().Transport
+	for rt != nil {
+		chain = append(chain, rt)
+		switch t := rt.(type) {
+		case *dcos.DefaultTransport:
+			rt = t.Base
+		case *AuthTransport:
+			rt = t.Base
+		case *RetryTransport:
+			rt = t.Base
+		case *LoggingTransport:
+			rt = t.Base
+		default:
+			rt = nil
+		}
+	}
+
+	return chain
+}
+
+/**
+ * HTTPTransport returns the innermost *http.Transport of the DC/OS HTTP client
+ */
+func HTTPTransport(client *dcos.APIClient) (*http.Transport, error) {
+	chain := transportChain(client)
+	if len(chain) > 0 {
+		if transport, ok := chain[len(chain)-1].(*http.Transport); ok {
+			return transport, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unable to find the HTTP transport of the DC/OS client")
+}
+
+// rewindableBody makes sure the request body can be sent more than once
+func rewindableBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return nil
+}
+
+// sleepContext waits for the given duration or until the context is done
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
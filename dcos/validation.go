@@ -3,8 +3,12 @@ package dcos
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mesosphere/terraform-provider-dcos/dcos/util"
 )
 
 // validateRegexp is borrowed from https://github.com/terraform-providers/terraform-provider-google/blob/c5bbdce38eb1a971c95691ee3d9f26efca1d595e/google/validation.go#L73-L83
@@ -19,3 +23,97 @@ func validateRegexp(re string) schema.SchemaValidateFunc {
 		return
 	}
 }
+
+// validateResourceLimit accepts a positive number or "unlimited"
+func validateResourceLimit(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value == "unlimited" {
+		return
+	}
+
+	if limit, err := strconv.ParseFloat(value, 64); err != nil || limit <= 0 {
+		errors = append(errors, fmt.Errorf(
+			"%q (%q) must be a positive number or \"unlimited\"", k, value))
+	}
+
+	return
+}
+
+// suppressEqualResourceLimits ignores differences in the notation of numeric
+// limits, eg. "1" and "1.0"
+func suppressEqualResourceLimits(k, old, new string, d *schema.ResourceData) bool {
+	oldLimit, oldErr := strconv.ParseFloat(old, 64)
+	newLimit, newErr := strconv.ParseFloat(new, 64)
+	if oldErr != nil || newErr != nil {
+		return old == new
+	}
+	return oldLimit == newLimit
+}
+
+// validateCron accepts the five field cron expressions Metronome accepts
+func validateCron(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if _, err := util.ParseCron(value); err != nil {
+		errors = append(errors, fmt.Errorf(
+			"%q (%q) is not a valid cron expression: %s", k, value, err.Error()))
+	}
+
+	return
+}
+
+// validateTimezone accepts IANA time zone names known to the time zone
+// database of the system
+func validateTimezone(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value == "" || value == "Local" {
+		errors = append(errors, fmt.Errorf(
+			"%q (%q) must be an IANA time zone name", k, value))
+		return
+	}
+
+	if _, err := time.LoadLocation(value); err != nil {
+		errors = append(errors, fmt.Errorf(
+			"%q (%q) is not a known IANA time zone: %s", k, value, err.Error()))
+	}
+
+	return
+}
+
+// validateMapFields checks the keys of a map, as nested schemas of maps are
+// not enforced. The values of TypeBool fields have to be booleans.
+func validateMapFields(fields map[string]schema.ValueType, required ...string) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(map[string]interface{})
+
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			valueType, ok := fields[key]
+			if !ok {
+				errors = append(errors, fmt.Errorf(
+					"%q has an unknown field %q", k, key))
+				continue
+			}
+
+			if valueType == schema.TypeBool {
+				if _, err := strconv.ParseBool(fmt.Sprint(value[key])); err != nil {
+					errors = append(errors, fmt.Errorf(
+						"%q (%q) must be true or false", k+"."+key, fmt.Sprint(value[key])))
+				}
+			}
+		}
+
+		for _, key := range required {
+			if _, ok := value[key]; !ok {
+				errors = append(errors, fmt.Errorf(
+					"%q requires the field %q", k, key))
+			}
+		}
+
+		return
+	}
+}